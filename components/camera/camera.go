@@ -107,6 +107,8 @@ type VideoSource interface {
 
 	// NextPointCloud returns the next immediately available point cloud, not necessarily one
 	// a part of a sequence. In the future, there could be streaming of point clouds.
+	// Implementations should honor ctx and return promptly with ctx.Err() once it is
+	// cancelled, as point clouds can be large and slow to produce or transfer.
 	//
 	//    myCamera, err := camera.FromRobot(machine, "my_camera")
 	//
@@ -134,6 +136,35 @@ func ReadImage(ctx context.Context, src gostream.VideoSource) (image.Image, func
 	return gostream.ReadImage(ctx, src)
 }
 
+// ReadNamedImage reads an image from the camera along with the name of the source it came from,
+// so that callers such as data capture can label the frame correctly. If the camera returns images
+// from multiple sources, the image whose source name matches the camera's ImageType is selected,
+// falling back to the first image returned by the camera.
+func ReadNamedImage(ctx context.Context, cam Camera) (NamedImage, resource.ResponseMetadata, error) {
+	imgs, md, err := cam.Images(ctx)
+	if err != nil {
+		return NamedImage{}, resource.ResponseMetadata{}, err
+	}
+	if len(imgs) == 0 {
+		return NamedImage{}, resource.ResponseMetadata{}, errors.New("camera returned no images")
+	}
+	if len(imgs) == 1 {
+		return imgs[0], md, nil
+	}
+	props, err := cam.Properties(ctx)
+	if err != nil {
+		return NamedImage{}, resource.ResponseMetadata{}, err
+	}
+	if props.ImageType != UnspecifiedStream {
+		for _, img := range imgs {
+			if img.SourceName == string(props.ImageType) {
+				return img, md, nil
+			}
+		}
+	}
+	return imgs[0], md, nil
+}
+
 type projectorProvider interface {
 	Projector(ctx context.Context) (transform.Projector, error)
 }
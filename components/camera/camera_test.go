@@ -4,6 +4,7 @@ import (
 	"context"
 	"image"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.viam.com/test"
@@ -15,6 +16,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/testutils/inject"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -253,3 +255,45 @@ func TestCameraWithProjector(t *testing.T) {
 
 	test.That(t, cam2.Close(context.Background()), test.ShouldBeNil)
 }
+
+func TestReadNamedImage(t *testing.T) {
+	colorImg := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	depthImg := image.NewGray16(image.Rect(0, 0, 4, 4))
+	ts := time.Now()
+
+	injectCamera := &inject.Camera{}
+	injectCamera.ImagesFunc = func(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+		return []camera.NamedImage{
+			{Image: colorImg, SourceName: "color"},
+			{Image: depthImg, SourceName: "depth"},
+		}, resource.ResponseMetadata{CapturedAt: ts}, nil
+	}
+	imageType := camera.DepthStream
+	injectCamera.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{ImageType: imageType}, nil
+	}
+
+	t.Run("selects the source matching the image type", func(t *testing.T) {
+		namedImg, md, err := camera.ReadNamedImage(context.Background(), injectCamera)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, namedImg.SourceName, test.ShouldEqual, "depth")
+		test.That(t, namedImg.Image, test.ShouldEqual, depthImg)
+		test.That(t, md.CapturedAt, test.ShouldEqual, ts)
+	})
+
+	t.Run("falls back to the first source", func(t *testing.T) {
+		imageType = camera.UnspecifiedStream
+		namedImg, _, err := camera.ReadNamedImage(context.Background(), injectCamera)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, namedImg.SourceName, test.ShouldEqual, "color")
+		test.That(t, namedImg.Image, test.ShouldEqual, colorImg)
+	})
+
+	t.Run("returns an error when no images are returned", func(t *testing.T) {
+		injectCamera.ImagesFunc = func(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+			return nil, resource.ResponseMetadata{}, nil
+		}
+		_, _, err := camera.ReadNamedImage(context.Background(), injectCamera)
+		test.That(t, err, test.ShouldBeError, errors.New("camera returned no images"))
+	})
+}
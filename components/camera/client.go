@@ -299,6 +299,11 @@ func (c *client) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, err
 	})
 	getPcdSpan.End()
 	if err != nil {
+		// the gRPC call is aborted as soon as ctx is done; surface the context error
+		// rather than the wrapped status so callers can check for cancellation.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, err
 	}
 
@@ -310,6 +315,9 @@ func (c *client) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, err
 		_, span := trace.StartSpan(ctx, "camera::client::NextPointCloud::ReadPCD")
 		defer span.End()
 
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		return pointcloud.ReadPCD(bytes.NewReader(resp.PointCloud))
 	}()
 }
@@ -388,8 +396,8 @@ func (c *client) Close(ctx context.Context) error {
 	c.healthyClientCh = nil
 	c.healthyClientChMu.Unlock()
 
-	// unsubscribe from all video streams that have been established with modular cameras
-
+	// unsubscribe from all video streams that have been established with modular cameras.
+	// Once this returns no SubscribeRTP callback will be invoked again.
 	c.unsubscribeAll(ctx)
 
 	// NOTE: (Nick S) we are intentionally releasing the lock before we wait for
@@ -725,6 +733,10 @@ func (c *client) trackName() string {
 	return c.Name().SDPTrackName()
 }
 
+// unsubscribeAll terminates every active RTP subscription. Closing each
+// *rtppassthrough.Buffer waits for any in flight callback to return and, as the
+// OnTrack goroutine only publishes to subscriptions in bufAndCBByID while holding
+// rtpPassthroughMu, no callback can fire after unsubscribeAll returns.
 func (c *client) unsubscribeAll(ctx context.Context) {
 	c.rtpPassthroughMu.Lock()
 	defer c.rtpPassthroughMu.Unlock()
@@ -735,6 +747,8 @@ func (c *client) unsubscribeAll(ctx context.Context) {
 			delete(c.bufAndCBByID, id)
 			bufAndCB.buf.Close()
 		}
+		// every child subscription has been terminated above
+		c.subParentToChildren = map[rtppassthrough.SubscriptionID][]rtppassthrough.SubscriptionID{}
 
 		// BEGIN TestWhyMustCallUnsubscribe
 		// if we are talking to a remote viam-sever we need to call RemoveStream so that
@@ -564,6 +564,107 @@ func TestClientWithInterceptor(t *testing.T) {
 	test.That(t, conn.Close(), test.ShouldBeNil)
 }
 
+func TestClientPointCloudHeader(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener1, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger.AsZap(), rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	pcA := pointcloud.New()
+	test.That(t, pcA.Set(pointcloud.NewVector(-1, 2, 3), nil), test.ShouldBeNil)
+	test.That(t, pcA.Set(pointcloud.NewVector(4, -5, 6.5), nil), test.ShouldBeNil)
+	test.That(t, pcA.Set(pointcloud.NewVector(0, 0, 0), nil), test.ShouldBeNil)
+
+	injectCamera := &inject.Camera{}
+	injectCamera.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		return pcA, nil
+	}
+
+	resources := map[resource.Name]camera.Camera{
+		camera.Named(testCameraName): injectCamera,
+	}
+	cameraSvc, err := resource.NewAPIResourceCollection(camera.API, resources)
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[camera.Camera](camera.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, cameraSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener1)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(
+		context.Background(),
+		listener1.Addr().String(),
+		logger,
+		rpc.WithUnaryClientInterceptor(contextutils.ContextWithMetadataUnaryClientInterceptor),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	camera1Client, err := camera.NewClientFromConn(context.Background(), conn, "", camera.Named(testCameraName), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	ctx, md := contextutils.ContextWithMetadata(context.Background())
+	pcB, err := camera1Client.NextPointCloud(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pcB.Size(), test.ShouldEqual, 3)
+	test.That(t, md[contextutils.PointCountMetadataKey], test.ShouldResemble, []string{fmt.Sprint(pcB.Size())})
+	test.That(t, md[contextutils.BoundsMinMetadataKey], test.ShouldResemble, []string{"-1,-5,0"})
+	test.That(t, md[contextutils.BoundsMaxMetadataKey], test.ShouldResemble, []string{"4,2,6.5"})
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+}
+
+func TestClientNextPointCloudCancel(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener1, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger.AsZap(), rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	// the injected camera blocks until the request context is cancelled, simulating a
+	// large point cloud that is still being produced when the caller gives up.
+	started := make(chan struct{})
+	injectCamera := &inject.Camera{}
+	injectCamera.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	resources := map[resource.Name]camera.Camera{
+		camera.Named(testCameraName): injectCamera,
+	}
+	cameraSvc, err := resource.NewAPIResourceCollection(camera.API, resources)
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[camera.Camera](camera.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, cameraSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener1)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	camera1Client, err := camera.NewClientFromConn(context.Background(), conn, "", camera.Named(testCameraName), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err = camera1Client.NextPointCloud(ctx)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 5*time.Second)
+}
+
 func TestClientStreamAfterClose(t *testing.T) {
 	// Set up gRPC server
 	logger := logging.NewTestLogger(t)
@@ -811,6 +912,68 @@ func TestMultiplexOverRemoteConnection(t *testing.T) {
 	greenLog(t, "unsubscribe")
 }
 
+func TestClientCloseTerminatesRTPSubscriptions(t *testing.T) {
+	logger := logging.NewTestLogger(t).Sublogger(t.Name())
+
+	remoteCfg := &config.Config{Components: []resource.Config{
+		{
+			Name:  "rtpPassthroughCamera",
+			API:   resource.NewAPI("rdk", "component", "camera"),
+			Model: resource.DefaultModelFamily.WithModel("fake"),
+			ConvertedAttributes: &fake.Config{
+				RTPPassthrough: true,
+			},
+		},
+	}}
+
+	remoteCtx, remoteRobot, addr, remoteWebSvc := setupRealRobot(t, remoteCfg, logger.Sublogger("remote"))
+	defer remoteRobot.Close(remoteCtx)
+	defer remoteWebSvc.Close(remoteCtx)
+
+	mainCfg := &config.Config{Remotes: []config.Remote{
+		{
+			Name:     "remote",
+			Address:  addr,
+			Insecure: true,
+		},
+	}}
+	mainCtx, mainRobot, _, mainWebSvc := setupRealRobot(t, mainCfg, logger.Sublogger("main"))
+	defer mainRobot.Close(mainCtx)
+	defer mainWebSvc.Close(mainCtx)
+
+	cameraClient, err := camera.FromRobot(mainRobot, "remote:rtpPassthroughCamera")
+	test.That(t, err, test.ShouldBeNil)
+
+	var mu sync.Mutex
+	closed := false
+	calledAfterClose := false
+	recvPktsCtx, recvPktsFn := context.WithCancel(context.Background())
+	timeoutCtx, cancel := context.WithTimeout(mainCtx, 10*time.Second)
+	defer cancel()
+	sub, err := cameraClient.(rtppassthrough.Source).SubscribeRTP(timeoutCtx, 512, func(pkts []*rtp.Packet) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			calledAfterClose = true
+		}
+		recvPktsFn()
+	})
+	test.That(t, err, test.ShouldBeNil)
+	<-recvPktsCtx.Done()
+
+	test.That(t, cameraClient.Close(mainCtx), test.ShouldBeNil)
+	mu.Lock()
+	closed = true
+	mu.Unlock()
+	test.That(t, sub.Terminated.Err(), test.ShouldBeError, context.Canceled)
+
+	// the fake camera publishes packets every 200ms, give it time to publish several more
+	time.Sleep(time.Second)
+	mu.Lock()
+	defer mu.Unlock()
+	test.That(t, calledAfterClose, test.ShouldBeFalse)
+}
+
 // Skipped due to
 // https://viam.atlassian.net/browse/RSDK-7637
 func TestMultiplexOverMultiHopRemoteConnection(t *testing.T) {
@@ -40,6 +40,8 @@ var (
 const (
 	initialWidth  = 1280
 	initialHeight = 720
+	// replayBufferSize bounds the number of RTP packets retained for replay to late subscribers.
+	replayBufferSize = 256
 )
 
 func init() {
@@ -79,6 +81,11 @@ func NewCamera(
 		bufAndCBByID:   make(map[rtppassthrough.SubscriptionID]bufAndCB),
 		logger:         logger,
 	}
+	if newConf.RTPPassthroughReplay {
+		if cam.replayBuf, err = rtppassthrough.NewReplayBuffer(replayBufferSize); err != nil {
+			return nil, err
+		}
+	}
 	src, err := camera.NewVideoSourceFromReader(ctx, cam, resModel, camera.ColorStream)
 	if err != nil {
 		return nil, err
@@ -107,6 +114,9 @@ type Config struct {
 	Height         int  `json:"height,omitempty"`
 	Animated       bool `json:"animated,omitempty"`
 	RTPPassthrough bool `json:"rtp_passthrough,omitempty"`
+	// RTPPassthroughReplay replays the most recent keyframe to new RTP subscribers
+	// so that decoding starts cleanly.
+	RTPPassthroughReplay bool `json:"rtp_passthrough_replay,omitempty"`
 }
 
 // Validate checks that the config attributes are valid for a fake camera.
@@ -115,6 +125,10 @@ func (conf *Config) Validate(path string) ([]string, error) {
 		return nil, errors.New("maximum supported pixel height or width for fake cameras is 10000 pixels")
 	}
 
+	if conf.RTPPassthroughReplay && !conf.RTPPassthrough {
+		return nil, errors.New("rtp_passthrough_replay requires rtp_passthrough to be enabled")
+	}
+
 	if conf.Height < 0 || conf.Width < 0 {
 		return nil, errors.New("cannot use negative pixel height and width for fake cameras")
 	}
@@ -217,6 +231,7 @@ type Camera struct {
 	cancelFn                context.CancelFunc
 	activeBackgroundWorkers sync.WaitGroup
 	bufAndCBByID            map[rtppassthrough.SubscriptionID]bufAndCB
+	replayBuf               *rtppassthrough.ReplayBuffer
 	cacheImage              image.Image
 	cachePointCloud         pointcloud.PointCloud
 	logger                  logging.Logger
@@ -318,6 +333,17 @@ func (c *Camera) SubscribeRTP(
 		return rtppassthrough.NilSubscription, err
 	}
 
+	// queue the buffered keyframe ahead of any live packets so the subscriber
+	// starts decoding on a keyframe
+	if c.replayBuf != nil {
+		if pkts := c.replayBuf.Replay(); len(pkts) > 0 {
+			if err := buf.Publish(func() { packetsCB(pkts) }); err != nil {
+				buf.Close()
+				return rtppassthrough.NilSubscription, err
+			}
+		}
+	}
+
 	c.bufAndCBByID[sub.ID] = bufAndCB{
 		cb:  packetsCB,
 		buf: buf,
@@ -393,6 +419,11 @@ func (c *Camera) startPassthrough() error {
 
 			// get current timestamp
 			c.mu.RLock()
+			// record while holding the lock so a concurrent SubscribeRTP either
+			// replays these packets or receives them live, never both
+			if c.replayBuf != nil {
+				c.replayBuf.Record(pkts)
+			}
 			for _, bufAndCB := range c.bufAndCBByID {
 				if err := bufAndCB.buf.Publish(func() {
 					c.logger.Infof("fake camera publishing %d packets", len(pkts))
@@ -22,6 +22,7 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/bluenviron/gortsplib/v4/pkg/ringbuffer"
 	"github.com/google/uuid"
@@ -50,6 +51,21 @@ type Buffer struct {
 	buffer       *ringbuffer.RingBuffer
 	err          atomic.Value
 	wg           sync.WaitGroup
+
+	// enqueuedAt holds the time each queued callback was published, oldest first.
+	statsMu    sync.Mutex
+	enqueuedAt []time.Time
+	dropped    uint64
+}
+
+// BufferStats describes how far a subscriber is lagging behind its publisher.
+type BufferStats struct {
+	// QueueDepth is the number of callbacks waiting to be executed.
+	QueueDepth int
+	// OldestQueuedWait is how long the oldest queued callback has been waiting.
+	OldestQueuedWait time.Duration
+	// Dropped is the number of callbacks dropped because the queue was full.
+	Dropped uint64
 }
 
 // NewSubscription allocates an rtppassthrough *Buffer and
@@ -99,13 +115,29 @@ func (w *Buffer) Publish(cb func()) error {
 	if err, ok := rawErr.(error); ok && err != nil {
 		return err
 	}
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
 	ok := w.buffer.Push(cb)
 	if !ok {
+		w.dropped++
 		return ErrQueueFull
 	}
+	w.enqueuedAt = append(w.enqueuedAt, time.Now())
 	return nil
 }
 
+// Stats returns the current lag of the subscriber, which is useful
+// for identifying which consumer is slow.
+func (w *Buffer) Stats() BufferStats {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+	stats := BufferStats{QueueDepth: len(w.enqueuedAt), Dropped: w.dropped}
+	if len(w.enqueuedAt) > 0 {
+		stats.OldestQueuedWait = time.Since(w.enqueuedAt[0])
+	}
+	return stats
+}
+
 func (w *Buffer) run() {
 	for {
 		cb, ok := w.buffer.Pull()
@@ -114,6 +146,12 @@ func (w *Buffer) run() {
 			return
 		}
 
+		// the callback was pushed and its enqueue time appended while holding statsMu,
+		// so by the time we acquire it here the matching entry is present.
+		w.statsMu.Lock()
+		w.enqueuedAt = w.enqueuedAt[1:]
+		w.statsMu.Unlock()
+
 		cb.(func())()
 	}
 }
@@ -3,9 +3,11 @@ package rtppassthrough
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
 )
 
 const queueSize int = 16
@@ -120,6 +122,50 @@ func TestStreamSubscription(t *testing.T) {
 		})
 	})
 
+	t.Run("Stats", func(t *testing.T) {
+		t.Run("reports growing lag when the callback is slow", func(t *testing.T) {
+			_, buffer, err := NewSubscription(queueSize)
+			test.That(t, err, test.ShouldBeNil)
+			defer buffer.Close()
+
+			stats := buffer.Stats()
+			test.That(t, stats.QueueDepth, test.ShouldEqual, 0)
+			test.That(t, stats.OldestQueuedWait, test.ShouldEqual, 0)
+
+			// the first callback blocks until released, simulating a slow consumer
+			release := make(chan struct{})
+			started := make(chan struct{})
+			err = buffer.Publish(func() {
+				close(started)
+				<-release
+			})
+			test.That(t, err, test.ShouldBeNil)
+			buffer.Start()
+			<-started
+
+			for i := 0; i < queueSize; i++ {
+				test.That(t, buffer.Publish(func() {}), test.ShouldBeNil)
+			}
+			test.That(t, buffer.Publish(func() {}), test.ShouldBeError, ErrQueueFull)
+
+			first := buffer.Stats()
+			test.That(t, first.QueueDepth, test.ShouldEqual, queueSize)
+			test.That(t, first.Dropped, test.ShouldEqual, 1)
+			time.Sleep(10 * time.Millisecond)
+			second := buffer.Stats()
+			test.That(t, second.QueueDepth, test.ShouldEqual, queueSize)
+			test.That(t, second.OldestQueuedWait, test.ShouldBeGreaterThan, first.OldestQueuedWait)
+
+			close(release)
+			testutils.WaitForAssertion(t, func(tb testing.TB) {
+				tb.Helper()
+				stats := buffer.Stats()
+				test.That(tb, stats.QueueDepth, test.ShouldEqual, 0)
+				test.That(tb, stats.OldestQueuedWait, test.ShouldEqual, 0)
+			})
+		})
+	})
+
 	t.Run("Close", func(t *testing.T) {
 		t.Run("succeeds if called before Start()", func(t *testing.T) {
 			_, buffer, err := NewSubscription(queueSize)
@@ -0,0 +1,111 @@
+package rtppassthrough
+
+import (
+	"sync"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+	"github.com/pion/rtp"
+	"github.com/pkg/errors"
+)
+
+// ErrReplaySize indicates that the ReplayBuffer size
+// must be greater than 0.
+var ErrReplaySize = errors.New("ReplayBuffer size must be greater than 0")
+
+// ReplayBuffer retains the most recent H264 keyframe (SPS / PPS / IDR) and the packets
+// which depend on it so that a subscriber joining mid-stream can be sent a
+// decodable sequence before receiving live packets, rather than starting on a
+// non-keyframe and producing garbled video until the next IDR.
+//
+// The buffer is bounded to maxPackets. If the packets following a keyframe exceed
+// that bound the buffer is invalidated, as replaying a truncated sequence would not
+// decode cleanly, and nothing is replayed until the next keyframe arrives.
+type ReplayBuffer struct {
+	mu         sync.Mutex
+	maxPackets int
+	pkts       []*rtp.Packet
+	inKeyframe bool
+	valid      bool
+}
+
+// NewReplayBuffer returns a ReplayBuffer which retains at most maxPackets packets.
+func NewReplayBuffer(maxPackets int) (*ReplayBuffer, error) {
+	if maxPackets <= 0 {
+		return nil, ErrReplaySize
+	}
+	return &ReplayBuffer{maxPackets: maxPackets}, nil
+}
+
+// Record adds packets published by the source to the buffer.
+// Packets received before the first keyframe are discarded.
+func (rb *ReplayBuffer) Record(pkts []*rtp.Packet) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	for _, pkt := range pkts {
+		isKeyframe := isH264Keyframe(pkt.Payload)
+		switch {
+		case isKeyframe && !rb.inKeyframe:
+			// start of a new keyframe, everything before it is no longer needed
+			rb.pkts = rb.pkts[:0]
+			rb.valid = true
+		case !rb.valid:
+			rb.inKeyframe = isKeyframe
+			continue
+		case len(rb.pkts) >= rb.maxPackets:
+			rb.pkts = rb.pkts[:0]
+			rb.valid = false
+			rb.inKeyframe = isKeyframe
+			continue
+		}
+		rb.inKeyframe = isKeyframe
+		rb.pkts = append(rb.pkts, pkt)
+	}
+}
+
+// Replay returns the buffered keyframe and its dependent packets in the order they
+// were recorded, or nil if no complete sequence is buffered.
+func (rb *ReplayBuffer) Replay() []*rtp.Packet {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if !rb.valid || len(rb.pkts) == 0 {
+		return nil
+	}
+	pkts := make([]*rtp.Packet, len(rb.pkts))
+	copy(pkts, rb.pkts)
+	return pkts
+}
+
+// isH264Keyframe returns true if the RTP payload carries (part of) an SPS, PPS or IDR
+// NALU, including when aggregated (STAP-A) or fragmented (FU-A).
+func isH264Keyframe(payload []byte) bool {
+	if len(payload) == 0 {
+		return false
+	}
+	switch typ := h264.NALUType(payload[0] & 0x1F); typ {
+	case h264.NALUTypeSTAPA:
+		payload = payload[1:]
+		for len(payload) > 2 {
+			size := int(payload[0])<<8 | int(payload[1])
+			payload = payload[2:]
+			if size == 0 || size > len(payload) {
+				return false
+			}
+			if isH264KeyframeNALUType(h264.NALUType(payload[0] & 0x1F)) {
+				return true
+			}
+			payload = payload[size:]
+		}
+		return false
+	case h264.NALUTypeFUA:
+		if len(payload) < 2 {
+			return false
+		}
+		return isH264KeyframeNALUType(h264.NALUType(payload[1] & 0x1F))
+	default:
+		return isH264KeyframeNALUType(typ)
+	}
+}
+
+func isH264KeyframeNALUType(typ h264.NALUType) bool {
+	return typ == h264.NALUTypeSPS || typ == h264.NALUTypePPS || typ == h264.NALUTypeIDR
+}
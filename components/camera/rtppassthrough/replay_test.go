@@ -0,0 +1,93 @@
+package rtppassthrough
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"go.viam.com/test"
+)
+
+func pkt(seq uint16, payload ...byte) *rtp.Packet {
+	return &rtp.Packet{Header: rtp.Header{SequenceNumber: seq}, Payload: payload}
+}
+
+var (
+	nonIDR = byte(0x41)
+	sps    = byte(0x67)
+	pps    = byte(0x68)
+	idr    = byte(0x65)
+	// FU-A fragments of an IDR and of a non-IDR slice
+	fuaIDRStart = []byte{0x7C, 0x85}
+	fuaIDREnd   = []byte{0x7C, 0x45}
+	fuaNonIDR   = []byte{0x5C, 0x81}
+	// STAP-A aggregating an SPS and a PPS
+	stapA = []byte{0x78, 0x00, 0x01, 0x67, 0x00, 0x01, 0x68}
+)
+
+func TestReplayBuffer(t *testing.T) {
+	t.Run("NewReplayBuffer returns an err if size is not positive", func(t *testing.T) {
+		_, err := NewReplayBuffer(0)
+		test.That(t, err, test.ShouldBeError, ErrReplaySize)
+		_, err = NewReplayBuffer(-1)
+		test.That(t, err, test.ShouldBeError, ErrReplaySize)
+	})
+
+	t.Run("replays nothing before the first keyframe", func(t *testing.T) {
+		rb, err := NewReplayBuffer(queueSize)
+		test.That(t, err, test.ShouldBeNil)
+		rb.Record([]*rtp.Packet{pkt(0, nonIDR), pkt(1, nonIDR)})
+		test.That(t, rb.Replay(), test.ShouldBeNil)
+	})
+
+	t.Run("a late subscriber receives the keyframe first", func(t *testing.T) {
+		rb, err := NewReplayBuffer(queueSize)
+		test.That(t, err, test.ShouldBeNil)
+		rb.Record([]*rtp.Packet{pkt(0, nonIDR), pkt(1, sps), pkt(2, pps)})
+		rb.Record([]*rtp.Packet{pkt(3, fuaIDRStart...), pkt(4, fuaIDREnd...)})
+		rb.Record([]*rtp.Packet{pkt(5, nonIDR), pkt(6, fuaNonIDR...)})
+
+		// a subscriber joining now is sent the replay ahead of live packets
+		sub, buf, err := NewSubscription(queueSize)
+		test.That(t, err, test.ShouldBeNil)
+		received := make(chan []*rtp.Packet, queueSize)
+		test.That(t, buf.Publish(func() { received <- rb.Replay() }), test.ShouldBeNil)
+		test.That(t, buf.Publish(func() { received <- []*rtp.Packet{pkt(7, nonIDR)} }), test.ShouldBeNil)
+		buf.Start()
+		defer buf.Close()
+		test.That(t, sub.Terminated.Err(), test.ShouldBeNil)
+
+		replayed := <-received
+		test.That(t, len(replayed), test.ShouldEqual, 6)
+		test.That(t, replayed[0].Payload[0], test.ShouldEqual, sps)
+		for i, p := range replayed {
+			test.That(t, p.SequenceNumber, test.ShouldEqual, uint16(i+1))
+		}
+		live := <-received
+		test.That(t, live[0].SequenceNumber, test.ShouldEqual, 7)
+	})
+
+	t.Run("a new keyframe replaces the previous one", func(t *testing.T) {
+		rb, err := NewReplayBuffer(queueSize)
+		test.That(t, err, test.ShouldBeNil)
+		rb.Record([]*rtp.Packet{pkt(0, idr), pkt(1, nonIDR)})
+		rb.Record([]*rtp.Packet{pkt(2, stapA...), pkt(3, idr), pkt(4, nonIDR)})
+		replayed := rb.Replay()
+		test.That(t, len(replayed), test.ShouldEqual, 3)
+		test.That(t, replayed[0].SequenceNumber, test.ShouldEqual, 2)
+	})
+
+	t.Run("is invalidated when the bound is exceeded until the next keyframe", func(t *testing.T) {
+		rb, err := NewReplayBuffer(2)
+		test.That(t, err, test.ShouldBeNil)
+		rb.Record([]*rtp.Packet{pkt(0, idr), pkt(1, nonIDR)})
+		test.That(t, len(rb.Replay()), test.ShouldEqual, 2)
+		rb.Record([]*rtp.Packet{pkt(2, nonIDR)})
+		test.That(t, rb.Replay(), test.ShouldBeNil)
+		rb.Record([]*rtp.Packet{pkt(3, nonIDR)})
+		test.That(t, rb.Replay(), test.ShouldBeNil)
+		rb.Record([]*rtp.Packet{pkt(4, idr)})
+		replayed := rb.Replay()
+		test.That(t, len(replayed), test.ShouldEqual, 1)
+		test.That(t, replayed[0].SequenceNumber, test.ShouldEqual, 4)
+	})
+}
@@ -3,13 +3,18 @@ package camera
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"image"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/camera/v1"
+	goutils "go.viam.com/utils"
 	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/logging"
@@ -18,6 +23,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/utils/contextutils"
 )
 
 // serviceServer implements the CameraService from camera.proto.
@@ -214,6 +220,9 @@ func (s *serviceServer) GetPointCloud(
 	if err != nil {
 		return nil, err
 	}
+	// report the size and bounds up front so clients can track progress without decoding the cloud.
+	// This errors when not called through a gRPC server (e.g. in tests) which is safe to ignore.
+	goutils.UncheckedError(grpc.SetHeader(ctx, pointCloudHeader(pc)))
 
 	var buf bytes.Buffer
 	buf.Grow(200 + (pc.Size() * 4 * 4)) // 4 numbers per point, each 4 bytes
@@ -230,6 +239,17 @@ func (s *serviceServer) GetPointCloud(
 	}, nil
 }
 
+// pointCloudHeader returns the gRPC response header metadata describing the point cloud.
+func pointCloudHeader(pc pointcloud.PointCloud) metadata.MD {
+	md := metadata.MD{contextutils.PointCountMetadataKey: []string{strconv.Itoa(pc.Size())}}
+	if pc.Size() > 0 {
+		meta := pc.MetaData()
+		md.Set(contextutils.BoundsMinMetadataKey, fmt.Sprintf("%v,%v,%v", meta.MinX, meta.MinY, meta.MinZ))
+		md.Set(contextutils.BoundsMaxMetadataKey, fmt.Sprintf("%v,%v,%v", meta.MaxX, meta.MaxY, meta.MaxZ))
+	}
+	return md
+}
+
 func (s *serviceServer) GetProperties(
 	ctx context.Context,
 	req *pb.GetPropertiesRequest,
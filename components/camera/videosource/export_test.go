@@ -0,0 +1,64 @@
+// export_test.go adds functionality to the videosource package that we only want to use and expose during testing.
+package videosource
+
+import (
+	"context"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// ResolveWebcamPath is resolveWebcamPath exported for tests.
+var ResolveWebcamPath = resolveWebcamPath
+
+// KnownIntrinsics is knownIntrinsics exported for tests.
+var KnownIntrinsics = knownIntrinsics
+
+// NewWebcamFromSource returns a webcam configured by conf that is backed by the given video source.
+func NewWebcamFromSource(
+	ctx context.Context,
+	name resource.Name,
+	src gostream.VideoSource,
+	conf WebcamConfig,
+) (camera.Camera, error) {
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	logger := logging.NewLogger(name.ShortName())
+	c := &monitoredWebcam{
+		Named:            name.AsNamed(),
+		logger:           logger,
+		originalLogger:   logger,
+		underlyingSource: src,
+		exposedSwapper:   gostream.NewHotSwappableVideoSource(src),
+		conf:             conf,
+		cancelCtx:        cancelCtx,
+		cancel:           cancel,
+	}
+	streamSource, err := c.newStreamSource(ctx, &conf)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	c.exposedStreamSource = streamSource
+	return c, nil
+}
+
+// SetWebcamSourceFinder makes a webcam returned by NewWebcamFromSource open the source returned by find
+// for its config whenever it reconnects or its driver is reinitialized.
+func SetWebcamSourceFinder(cam camera.Camera, find func(conf WebcamConfig) (gostream.VideoSource, error)) {
+	cam.(*monitoredWebcam).findSource = func(
+		ctx context.Context, conf *WebcamConfig, label string, logger logging.Logger,
+	) (gostream.VideoSource, string, error) {
+		src, err := find(*conf)
+		return src, conf.Path, err
+	}
+}
+
+// ErrReconnectAttemptsExceeded is errReconnectAttemptsExceeded exported for tests.
+var ErrReconnectAttemptsExceeded = errReconnectAttemptsExceeded
+
+// Reconnect runs the reconnect policy described by conf.
+func Reconnect(ctx context.Context, conf *ReconnectConfig, tryReconnect func() error) error {
+	return newReconnectPolicy(conf).reconnect(ctx, tryReconnect)
+}
@@ -148,6 +148,48 @@ func webcamsToMap(webcams []*pb.Webcam) debugLogger.InfoMap {
 	return info
 }
 
+// WebcamInfo is the structured form of a discovered webcam.
+type WebcamInfo struct {
+	Name       string     `json:"name"`
+	ID         string     `json:"id"`
+	Label      string     `json:"label"`
+	Status     string     `json:"status"`
+	Properties []Property `json:"properties"`
+}
+
+// Property is a single video mode supported by a discovered webcam.
+type Property struct {
+	Width     int     `json:"width_px"`
+	Height    int     `json:"height_px"`
+	Format    string  `json:"frame_format"`
+	FrameRate float32 `json:"frame_rate"`
+}
+
+// WebcamsToInfo converts discovered webcams into structured info suitable for programmatic use,
+// e.g. populating a device selection dropdown.
+func WebcamsToInfo(webcams []*pb.Webcam) []WebcamInfo {
+	infos := make([]WebcamInfo, 0, len(webcams))
+	for _, w := range webcams {
+		info := WebcamInfo{
+			Name:       w.GetName(),
+			ID:         w.GetId(),
+			Label:      w.GetLabel(),
+			Status:     w.GetStatus(),
+			Properties: make([]Property, 0, len(w.GetProperties())),
+		}
+		for _, p := range w.GetProperties() {
+			info.Properties = append(info.Properties, Property{
+				Width:     int(p.GetWidthPx()),
+				Height:    int(p.GetHeightPx()),
+				Format:    p.GetFrameFormat(),
+				FrameRate: p.GetFrameRate(),
+			})
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
 func getProperties(d driver.Driver) (_ []prop.Media, err error) {
 	// Need to open driver to get properties
 	if d.Status() == driver.StateClosed {
@@ -174,6 +216,20 @@ type WebcamConfig struct {
 	Width                int                                `json:"width_px,omitempty"`
 	Height               int                                `json:"height_px,omitempty"`
 	FrameRate            float32                            `json:"frame_rate,omitempty"`
+	StreamWidth          int                                `json:"stream_width_px,omitempty"`
+	StreamHeight         int                                `json:"stream_height_px,omitempty"`
+	Reconnect            *ReconnectConfig                   `json:"reconnect,omitempty"`
+}
+
+// ReconnectConfig configures how aggressively a disconnected webcam is reconnected.
+// Each failed attempt multiplies the delay before the next one, up to a maximum delay.
+type ReconnectConfig struct {
+	InitialDelayMs int     `json:"initial_delay_ms,omitempty"`
+	MaxDelayMs     int     `json:"max_delay_ms,omitempty"`
+	Multiplier     float64 `json:"multiplier,omitempty"`
+	// MaxAttempts is the number of failed attempts after which the camera gives up reconnecting.
+	// Zero means it never gives up.
+	MaxAttempts int `json:"max_attempts,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -184,9 +240,106 @@ func (c WebcamConfig) Validate(path string) ([]string, error) {
 			c.Height, c.Width)
 	}
 
+	if c.StreamWidth < 0 || c.StreamHeight < 0 {
+		return nil, fmt.Errorf(
+			"got illegal negative dimensions for stream_width_px and stream_height_px (%d, %d) fields set for webcam camera",
+			c.StreamWidth, c.StreamHeight)
+	}
+
+	if (c.Width != 0 && c.StreamWidth > c.Width) || (c.Height != 0 && c.StreamHeight > c.Height) {
+		return nil, fmt.Errorf(
+			"stream resolution (%dx%d) can't be larger than the capture resolution (%dx%d) set for webcam camera",
+			c.StreamWidth, c.StreamHeight, c.Width, c.Height)
+	}
+
+	if c.Reconnect != nil {
+		if err := c.Reconnect.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	return []string{}, nil
 }
 
+func (c ReconnectConfig) validate() error {
+	if c.InitialDelayMs < 0 || c.MaxDelayMs < 0 || c.MaxAttempts < 0 {
+		return fmt.Errorf(
+			"got illegal negative values for initial_delay_ms, max_delay_ms or max_attempts (%d, %d, %d) fields set for webcam camera",
+			c.InitialDelayMs, c.MaxDelayMs, c.MaxAttempts)
+	}
+	if c.Multiplier != 0 && c.Multiplier < 1 {
+		return fmt.Errorf("reconnect multiplier must be at least 1, got %f", c.Multiplier)
+	}
+	if c.InitialDelayMs != 0 && c.MaxDelayMs != 0 && c.MaxDelayMs < c.InitialDelayMs {
+		return fmt.Errorf("reconnect max_delay_ms (%d) can't be lower than initial_delay_ms (%d)", c.MaxDelayMs, c.InitialDelayMs)
+	}
+	return nil
+}
+
+const (
+	defaultReconnectDelay    = 500 * time.Millisecond
+	defaultReconnectMaxDelay = 30 * time.Second
+)
+
+// reconnectPolicy is a validated ReconnectConfig with defaults applied. By default a webcam
+// retries every 500ms forever.
+type reconnectPolicy struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	maxAttempts  int
+}
+
+func newReconnectPolicy(conf *ReconnectConfig) reconnectPolicy {
+	p := reconnectPolicy{
+		initialDelay: defaultReconnectDelay,
+		maxDelay:     defaultReconnectMaxDelay,
+		multiplier:   1,
+	}
+	if conf == nil {
+		return p
+	}
+	if conf.InitialDelayMs > 0 {
+		p.initialDelay = time.Duration(conf.InitialDelayMs) * time.Millisecond
+	}
+	if conf.MaxDelayMs > 0 {
+		p.maxDelay = time.Duration(conf.MaxDelayMs) * time.Millisecond
+	}
+	if p.maxDelay < p.initialDelay {
+		p.maxDelay = p.initialDelay
+	}
+	if conf.Multiplier > 0 {
+		p.multiplier = conf.Multiplier
+	}
+	p.maxAttempts = conf.MaxAttempts
+	return p
+}
+
+// errReconnectAttemptsExceeded is returned once a webcam has given up reconnecting.
+var errReconnectAttemptsExceeded = errors.New("camera could not be reconnected; giving up")
+
+// reconnect calls tryReconnect, backing off between failed attempts according to the policy, until it
+// succeeds, the context is done, or the maximum number of attempts is exceeded.
+func (p reconnectPolicy) reconnect(ctx context.Context, tryReconnect func() error) error {
+	delay := p.initialDelay
+	for attempt := 1; ; attempt++ {
+		if !goutils.SelectContextOrWait(ctx, delay) {
+			return ctx.Err()
+		}
+		err := tryReconnect()
+		if err == nil {
+			return nil
+		}
+		if p.maxAttempts > 0 && attempt >= p.maxAttempts {
+			return multierr.Combine(errReconnectAttemptsExceeded, err)
+		}
+		delay = time.Duration(float64(delay) * p.multiplier)
+		if delay > p.maxDelay {
+			delay = p.maxDelay
+		}
+	}
+}
+
 func (c WebcamConfig) needsDriverReinit(other WebcamConfig) bool {
 	return !(c.Format == other.Format &&
 		c.Path == other.Path &&
@@ -238,7 +391,9 @@ func makeConstraints(conf *WebcamConfig, debug bool, logger logging.Logger) medi
 	}
 }
 
-// findAndMakeVideoSource finds a video device and returns a video source with that video device as the source.
+// findAndMakeVideoSource finds a video device and returns a video source with that video device as the source,
+// along with its label. The device is the one the configured video path resolves to, else the one with the
+// given label, else any video device.
 func findAndMakeVideoSource(
 	ctx context.Context,
 	conf *WebcamConfig,
@@ -246,10 +401,18 @@ func findAndMakeVideoSource(
 	logger logging.Logger,
 ) (gostream.VideoSource, string, error) {
 	mediadevicescamera.Initialize()
+	if conf.Path != "" {
+		// the path is resolved on every call, as the device it links to may change when it is reconnected
+		resolvedLabel, err := resolveWebcamPath(conf.Path, getVideoDrivers)
+		if err != nil {
+			return nil, "", err
+		}
+		label = resolvedLabel
+	}
 	debug := conf.Debug
 	constraints := makeConstraints(conf, debug, logger)
 	if label != "" {
-		cam, err := tryWebcamOpen(ctx, conf, label, false, constraints, logger)
+		cam, err := tryWebcamOpen(ctx, conf, label, constraints, logger)
 		if err != nil {
 			return nil, "", errors.Wrap(err, "cannot open webcam")
 		}
@@ -279,6 +442,37 @@ func getLabelFromVideoSource(src gostream.VideoSource, logger logging.Logger) st
 	return labels[0]
 }
 
+// resolveWebcamPath resolves the configured video path against the discovered video devices and
+// returns the label of the single device it identifies. If no device or more than one device matches,
+// an error listing the available devices is returned.
+func resolveWebcamPath(path string, getDrivers func() []driver.Driver) (string, error) {
+	targets := map[string]struct{}{filepath.Base(path): {}}
+	if resolvedPath, err := filepath.EvalSymlinks(path); err == nil {
+		targets[filepath.Base(resolvedPath)] = struct{}{}
+	}
+
+	var available, matches []string
+	for _, d := range getDrivers() {
+		labels := strings.Split(d.Info().Label, mediadevicescamera.LabelSeparator)
+		available = append(available, labels[0])
+		for _, label := range labels {
+			if _, ok := targets[label]; ok {
+				matches = append(matches, labels[0])
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return "", errors.Errorf("no webcam found matching video_path %q; available webcams: %v", path, available)
+	default:
+		return "", errors.Errorf("video_path %q matches multiple webcams %v; please specify a unique video_path", path, matches)
+	}
+}
+
 // NewWebcam returns a new source based on a webcam discovered from the given config.
 func NewWebcam(
 	ctx context.Context,
@@ -291,6 +485,7 @@ func NewWebcam(
 		Named:          conf.ResourceName().AsNamed(),
 		logger:         logging.FromZapCompatible(logger.With("camera_name", conf.ResourceName().ShortName())),
 		originalLogger: logger,
+		findSource:     findAndMakeVideoSource,
 		cancelCtx:      cancelCtx,
 		cancel:         cancel,
 	}
@@ -347,52 +542,95 @@ func (c *monitoredWebcam) Reconfigure(
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	cameraModel := camera.NewPinholeModelWithBrownConradyDistortion(newConf.CameraParameters, newConf.DistortionParameters)
+	reinit := c.underlyingSource == nil || c.conf.needsDriverReinit(*newConf)
+	canRollBack := c.underlyingSource != nil
+	prevTargetPath := c.targetPath
+	// rollBack reopens the camera with its previous config if the driver was reinitialized for the new
+	// config but the camera can't be used with it, so a failed reconfiguration leaves the camera as it was.
+	rollBack := func(err error) error {
+		if !reinit || !canRollBack {
+			return err
+		}
+		c.targetPath = prevTargetPath
+		if rollBackErr := c.reconnectCamera(&c.conf); rollBackErr != nil {
+			return multierr.Combine(err, errors.Wrap(rollBackErr, "failed to reopen camera with its previous config"))
+		}
+		return err
+	}
+
+	if reinit {
+		c.logger.CDebug(ctx, "reinitializing driver")
+
+		// a configured video path is resolved to a device when the camera is reconnected, else any device is used
+		c.targetPath = ""
+		if err := c.reconnectCamera(newConf); err != nil {
+			return rollBack(err)
+		}
+
+		c.hasLoggedIntrinsicsInfo = false
+	}
+
+	intrinsics := newConf.CameraParameters
+	if intrinsics == nil {
+		intrinsics = c.embeddedIntrinsics(ctx, newConf)
+	}
+	cameraModel := camera.NewPinholeModelWithBrownConradyDistortion(intrinsics, newConf.DistortionParameters)
 	projector, err := camera.WrapVideoSourceWithProjector(
 		ctx,
-		&noopCloser{c},
+		&noopCloser{c.exposedSwapper},
 		&cameraModel,
 		camera.ColorStream,
 	)
 	if err != nil {
-		return err
+		return rollBack(err)
+	}
+	streamSource, err := c.newStreamSource(ctx, newConf)
+	if err != nil {
+		goutils.UncheckedError(projector.Close(ctx))
+		return rollBack(err)
 	}
-
-	needDriverReinit := c.conf.needsDriverReinit(*newConf)
 	if c.exposedProjector != nil {
 		goutils.UncheckedError(c.exposedProjector.Close(ctx))
 	}
 	c.exposedProjector = projector
-
-	if c.underlyingSource != nil && !needDriverReinit {
-		c.conf = *newConf
-		return nil
+	if c.exposedStreamSource != nil {
+		goutils.UncheckedError(c.exposedStreamSource.Close(ctx))
 	}
-	c.logger.CDebug(ctx, "reinitializing driver")
-
-	c.targetPath = newConf.Path
-	if err := c.reconnectCamera(newConf); err != nil {
-		return err
-	}
-
-	c.hasLoggedIntrinsicsInfo = false
+	c.exposedStreamSource = streamSource
 
 	// only set once we're good
 	c.conf = *newConf
 	return nil
 }
 
-// tryWebcamOpen uses getNamedVideoSource to try and find a video device (gostream.MediaSource).
+// newStreamSource returns the source that is streamed from the camera. Frames are captured at the
+// device's resolution and downscaled if a separate stream resolution is configured. It assumes a lock is held.
+func (c *monitoredWebcam) newStreamSource(ctx context.Context, conf *WebcamConfig) (gostream.VideoSource, error) {
+	if conf.StreamWidth == 0 && conf.StreamHeight == 0 {
+		return &noopCloser{c.exposedSwapper}, nil
+	}
+	if provider, ok := c.underlyingSource.(gostream.VideoPropertyProvider); ok {
+		props, err := provider.MediaProperties(ctx)
+		if err == nil && props.Width > 0 && props.Height > 0 &&
+			(conf.StreamWidth > props.Width || conf.StreamHeight > props.Height) {
+			return nil, errors.Errorf("requested stream resolution (%dx%d) is larger than the resolution "+
+				"the webcam is capturing at (%dx%d)", conf.StreamWidth, conf.StreamHeight, props.Width, props.Height)
+		}
+	}
+	// a zero width or height preserves the aspect ratio of the captured frames
+	return gostream.NewResizeVideoSource(&noopCloser{c.exposedSwapper}, conf.StreamWidth, conf.StreamHeight), nil
+}
+
+// tryWebcamOpen opens the video device (gostream.MediaSource) with the given label.
 // If successful, it will wrap that MediaSource in a camera.
 func tryWebcamOpen(
 	ctx context.Context,
 	conf *WebcamConfig,
-	path string,
-	fromLabel bool,
+	label string,
 	constraints mediadevices.MediaStreamConstraints,
 	logger logging.Logger,
 ) (gostream.VideoSource, error) {
-	source, err := getNamedVideoSource(path, fromLabel, constraints, logger)
+	source, err := gostream.GetNamedVideoSource(label, constraints, logger.AsZap())
 	if err != nil {
 		return nil, err
 	}
@@ -414,43 +652,34 @@ func tryWebcamOpen(
 	return source, nil
 }
 
-// getNamedVideoSource attempts to find a video device (not a screen) by the given name.
-// First it will try to use the path name after evaluating any symbolic links. If
-// evaluation fails, it will try to use the path name as provided.
-func getNamedVideoSource(
-	path string,
-	fromLabel bool,
-	constraints mediadevices.MediaStreamConstraints,
-	logger logging.Logger,
-) (gostream.MediaSource[image.Image], error) {
-	if !fromLabel {
-		resolvedPath, err := filepath.EvalSymlinks(path)
-		if err == nil {
-			path = resolvedPath
-		}
-	}
-	return gostream.GetNamedVideoSource(filepath.Base(path), constraints, logger.AsZap())
-}
-
 // monitoredWebcam tries to ensure its underlying camera stays connected.
 type monitoredWebcam struct {
 	resource.Named
 	mu                      sync.RWMutex
 	hasLoggedIntrinsicsInfo bool
 
-	underlyingSource gostream.VideoSource
-	exposedSwapper   gostream.HotSwappableVideoSource
-	exposedProjector camera.VideoSource
+	underlyingSource    gostream.VideoSource
+	exposedSwapper      gostream.HotSwappableVideoSource
+	exposedProjector    camera.VideoSource
+	exposedStreamSource gostream.VideoSource
 
-	// this is returned to us as a label in mediadevices but our config
-	// treats it as a video path.
+	// the mediadevices label of the video device the camera last opened, which is reopened when the
+	// camera is reconnected unless the config's video path resolves to another device.
 	targetPath string
 	conf       WebcamConfig
+	// findSource finds & opens the video device the camera is configured to use, e.g. findAndMakeVideoSource.
+	findSource func(
+		ctx context.Context, conf *WebcamConfig, label string, logger logging.Logger,
+	) (gostream.VideoSource, string, error)
+
+	capturedAtMu   sync.Mutex
+	lastCapturedAt time.Time
 
 	cancelCtx               context.Context
 	cancel                  func()
 	closed                  bool
 	disconnected            bool
+	reconnectErr            error
 	activeBackgroundWorkers sync.WaitGroup
 	logger                  logging.Logger
 	originalLogger          logging.Logger
@@ -494,7 +723,7 @@ func (c *monitoredWebcam) reconnectCamera(conf *WebcamConfig) error {
 		c.underlyingSource = nil
 	}
 
-	newSrc, foundLabel, err := findAndMakeVideoSource(c.cancelCtx, conf, c.targetPath, c.logger)
+	newSrc, foundLabel, err := c.findSource(c.cancelCtx, conf, c.targetPath, c.logger)
 	if err != nil {
 		// If we are on a Jetson Orin AGX, we need to validate hardware/software setup.
 		// If not, simply pass through the error.
@@ -514,10 +743,9 @@ func (c *monitoredWebcam) reconnectCamera(conf *WebcamConfig) error {
 	}
 	c.underlyingSource = newSrc
 	c.disconnected = false
+	c.reconnectErr = nil
 	c.closed = false
-	if c.targetPath == "" {
-		c.targetPath = foundLabel
-	}
+	c.targetPath = foundLabel
 	c.logger = logging.FromZapCompatible(c.originalLogger.With("camera_label", c.targetPath))
 
 	return nil
@@ -556,25 +784,29 @@ func (c *monitoredWebcam) Monitor() {
 				c.mu.Unlock()
 
 				logger.Error("camera no longer connected; reconnecting")
-				for {
-					if !goutils.SelectContextOrWait(c.cancelCtx, wait) {
-						return
+				c.mu.RLock()
+				policy := newReconnectPolicy(c.conf.Reconnect)
+				c.mu.RUnlock()
+				err = policy.reconnect(c.cancelCtx, func() error {
+					c.mu.Lock()
+					defer c.mu.Unlock()
+
+					if err := c.reconnectCamera(&c.conf); err != nil {
+						c.logger.Errorw("failed to reconnect camera", "error", err)
+						return err
 					}
-					cont := func() bool {
-						c.mu.Lock()
-						defer c.mu.Unlock()
-
-						if err := c.reconnectCamera(&c.conf); err != nil {
-							c.logger.Errorw("failed to reconnect camera", "error", err)
-							return true
-						}
-						c.logger.Infow("camera reconnected")
-						return false
-					}()
-					if cont {
-						continue
-					}
-					break
+					c.logger.Infow("camera reconnected")
+					return nil
+				})
+				switch {
+				case err == nil:
+				case errors.Is(err, errReconnectAttemptsExceeded):
+					c.mu.Lock()
+					c.reconnectErr = err
+					c.mu.Unlock()
+					logger.Errorw("giving up reconnecting camera until it is reconfigured", "error", err)
+				default:
+					return
 				}
 			}
 		}
@@ -591,19 +823,43 @@ func (c *monitoredWebcam) Projector(ctx context.Context) (transform.Projector, e
 }
 
 func (c *monitoredWebcam) Images(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
-	if c, ok := c.underlyingSource.(camera.ImagesSource); ok {
-		return c.Images(ctx)
+	// the lock isn't held while reading, so a hung device doesn't block reconnecting or reconfiguring
+	c.mu.RLock()
+	if err := c.ensureActive(); err != nil {
+		c.mu.RUnlock()
+		return nil, resource.ResponseMetadata{}, err
+	}
+	source := c.underlyingSource
+	c.mu.RUnlock()
+
+	if imagesSource, ok := source.(camera.ImagesSource); ok {
+		return imagesSource.Images(ctx)
 	}
-	img, release, err := camera.ReadImage(ctx, c.underlyingSource)
+	img, release, err := camera.ReadImage(ctx, source)
 	if err != nil {
 		return nil, resource.ResponseMetadata{}, errors.Wrap(err, "monitoredWebcam: call to get Images failed")
 	}
+	capturedAt := c.captureTime()
 	defer func() {
 		if release != nil {
 			release()
 		}
 	}()
-	return []camera.NamedImage{{img, c.Name().Name}}, resource.ResponseMetadata{time.Now()}, nil
+	return []camera.NamedImage{{img, c.Name().Name}}, resource.ResponseMetadata{CapturedAt: capturedAt}, nil
+}
+
+// captureTime returns the best-effort capture time of a frame that was just received.
+// The device does not report when a frame was captured so the receive time is used, adjusted
+// if necessary so that successive frames always carry strictly increasing timestamps.
+func (c *monitoredWebcam) captureTime() time.Time {
+	c.capturedAtMu.Lock()
+	defer c.capturedAtMu.Unlock()
+	capturedAt := time.Now()
+	if !capturedAt.After(c.lastCapturedAt) {
+		capturedAt = c.lastCapturedAt.Add(time.Nanosecond)
+	}
+	c.lastCapturedAt = capturedAt
+	return capturedAt
 }
 
 func (c *monitoredWebcam) Stream(ctx context.Context, errHandlers ...gostream.ErrorHandler) (gostream.VideoStream, error) {
@@ -612,7 +868,7 @@ func (c *monitoredWebcam) Stream(ctx context.Context, errHandlers ...gostream.Er
 	if err := c.ensureActive(); err != nil {
 		return nil, err
 	}
-	return c.exposedSwapper.Stream(ctx, errHandlers...)
+	return c.exposedStreamSource.Stream(ctx, errHandlers...)
 }
 
 func (c *monitoredWebcam) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
@@ -624,9 +880,8 @@ func (c *monitoredWebcam) NextPointCloud(ctx context.Context) (pointcloud.PointC
 	return c.exposedProjector.NextPointCloud(ctx)
 }
 
+// driverInfo assumes a read lock is held.
 func (c *monitoredWebcam) driverInfo() (driver.Info, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
 	if c.underlyingSource == nil {
 		return driver.Info{}, errors.New("no underlying source found in camera")
 	}
@@ -637,63 +892,85 @@ func (c *monitoredWebcam) driverInfo() (driver.Info, error) {
 	return d.Info(), nil
 }
 
-func (c *monitoredWebcam) Properties(ctx context.Context) (camera.Properties, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if err := c.ensureActive(); err != nil {
-		return camera.Properties{}, err
+// knownIntrinsics looks up the intrinsics of a camera model in the map built using viam camera
+// calibration here https://github.com/viam-labs/camera-calibration/tree/main.
+// The driver's model name is tried first, falling back to its label.
+func knownIntrinsics(info driver.Info) (transform.PinholeCameraIntrinsics, string, bool) {
+	keys := []string{
+		info.Name,
+		strings.Split(info.Name, mediadevicescamera.LabelSeparator)[0],
+		strings.Split(info.Label, mediadevicescamera.LabelSeparator)[0],
 	}
+	for _, key := range keys {
+		if cameraIntrinsics, exists := data[key]; exists {
+			return cameraIntrinsics, key, true
+		}
+	}
+	return transform.PinholeCameraIntrinsics{}, "", false
+}
 
-	props, err := c.exposedProjector.Properties(ctx)
+// embeddedIntrinsics returns the known intrinsics of the underlying camera if they match the
+// configured resolution, or nil otherwise. It assumes a read lock is held.
+func (c *monitoredWebcam) embeddedIntrinsics(ctx context.Context, conf *WebcamConfig) *transform.PinholeCameraIntrinsics {
+	dInfo, err := c.driverInfo()
 	if err != nil {
-		return camera.Properties{}, err
+		if !c.hasLoggedIntrinsicsInfo {
+			c.logger.CErrorw(ctx, "can't find driver info for camera")
+			c.hasLoggedIntrinsicsInfo = true
+		}
+		return nil
 	}
-	// Looking for intrinsics in map built using viam camera
-	// calibration here https://github.com/viam-labs/camera-calibration/tree/main
-	if props.IntrinsicParams == nil {
-		dInfo, err := c.driverInfo()
-		if err != nil {
+
+	cameraIntrinsics, model, exists := knownIntrinsics(dInfo)
+	if !exists {
+		if !c.hasLoggedIntrinsicsInfo {
+			c.logger.CInfo(ctx, "camera model not found in known camera models for: ", dInfo.Name, ". returning "+
+				"properties without intrinsics")
+			c.hasLoggedIntrinsicsInfo = true
+		}
+		return nil
+	}
+	if conf.Width != 0 {
+		if conf.Width != cameraIntrinsics.Width {
 			if !c.hasLoggedIntrinsicsInfo {
-				c.logger.CErrorw(ctx, "can't find driver info for camera")
+				c.logger.CInfo(ctx, "camera model found in known camera models for: ", model, " but "+
+					"intrinsics width doesn't match configured image width")
 				c.hasLoggedIntrinsicsInfo = true
 			}
+			return nil
 		}
-
-		cameraIntrinsics, exists := data[dInfo.Name]
-		if !exists {
+	}
+	if conf.Height != 0 {
+		if conf.Height != cameraIntrinsics.Height {
 			if !c.hasLoggedIntrinsicsInfo {
-				c.logger.CInfo(ctx, "camera model not found in known camera models for: ", dInfo.Name, ". returning "+
-					"properties without intrinsics")
+				c.logger.CInfo(ctx, "camera model found in known camera models for: ", model, " but "+
+					"intrinsics height doesn't match configured image height")
 				c.hasLoggedIntrinsicsInfo = true
 			}
-			return props, nil
-		}
-		if c.conf.Width != 0 {
-			if c.conf.Width != cameraIntrinsics.Width {
-				if !c.hasLoggedIntrinsicsInfo {
-					c.logger.CInfo(ctx, "camera model found in known camera models for: ", dInfo.Name, " but "+
-						"intrinsics width doesn't match configured image width")
-					c.hasLoggedIntrinsicsInfo = true
-				}
-				return props, nil
-			}
-		}
-		if c.conf.Height != 0 {
-			if c.conf.Height != cameraIntrinsics.Height {
-				if !c.hasLoggedIntrinsicsInfo {
-					c.logger.CInfo(ctx, "camera model found in known camera models for: ", dInfo.Name, " but "+
-						"intrinsics height doesn't match configured image height")
-					c.hasLoggedIntrinsicsInfo = true
-				}
-				return props, nil
-			}
-		}
-		if !c.hasLoggedIntrinsicsInfo {
-			c.logger.CInfo(ctx, "Intrinsics are known for camera model: ", dInfo.Name, ". adding intrinsics "+
-				"to camera properties")
-			c.hasLoggedIntrinsicsInfo = true
+			return nil
 		}
-		props.IntrinsicParams = &cameraIntrinsics
+	}
+	if !c.hasLoggedIntrinsicsInfo {
+		c.logger.CInfo(ctx, "Intrinsics are known for camera model: ", model, ". adding intrinsics "+
+			"to camera properties")
+		c.hasLoggedIntrinsicsInfo = true
+	}
+	return &cameraIntrinsics
+}
+
+func (c *monitoredWebcam) Properties(ctx context.Context) (camera.Properties, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.ensureActive(); err != nil {
+		return camera.Properties{}, err
+	}
+
+	props, err := c.exposedProjector.Properties(ctx)
+	if err != nil {
+		return camera.Properties{}, err
+	}
+	if props.IntrinsicParams == nil {
+		props.IntrinsicParams = c.embeddedIntrinsics(ctx, &c.conf)
 	}
 	return props, nil
 }
@@ -707,12 +984,36 @@ func (c *monitoredWebcam) ensureActive() error {
 	if c.closed {
 		return errClosed
 	}
+	if c.reconnectErr != nil {
+		return c.reconnectErr
+	}
 	if c.disconnected {
 		return errDisconnected
 	}
 	return nil
 }
 
+// DoCommand supports the "status" command, which reports whether the camera is connected
+// and any error preventing it from being used.
+func (c *monitoredWebcam) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd["command"]
+	if !ok {
+		return nil, errors.New("missing 'command' value")
+	}
+	switch name {
+	case "status":
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		status := map[string]interface{}{"connected": !c.disconnected && c.reconnectErr == nil}
+		if err := c.ensureActive(); err != nil {
+			status["error"] = err.Error()
+		}
+		return status, nil
+	default:
+		return nil, fmt.Errorf("no such command: %s", name)
+	}
+}
+
 func (c *monitoredWebcam) Close(ctx context.Context) error {
 	c.mu.Lock()
 	if c.closed {
@@ -731,6 +1032,9 @@ func (c *monitoredWebcam) Close(ctx context.Context) error {
 	if c.exposedProjector != nil {
 		err = multierr.Combine(err, c.exposedProjector.Close(ctx))
 	}
+	if c.exposedStreamSource != nil {
+		err = multierr.Combine(err, c.exposedStreamSource.Close(ctx))
+	}
 	if c.underlyingSource != nil {
 		err = multierr.Combine(err, c.underlyingSource.Close(ctx))
 	}
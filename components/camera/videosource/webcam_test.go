@@ -2,14 +2,21 @@ package videosource_test
 
 import (
 	"context"
+	"image"
 	"testing"
+	"time"
 
 	"github.com/pion/mediadevices/pkg/driver"
 	"github.com/pion/mediadevices/pkg/prop"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/camera/v1"
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/camera/videosource"
+	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
 )
 
 // fakeDriver is a driver has a label and media properties.
@@ -54,3 +61,264 @@ func TestDiscoveryWebcam(t *testing.T) {
 	test.That(t, respProps[0].FrameFormat, test.ShouldResemble, "some format")
 	test.That(t, respProps[0].FrameRate, test.ShouldResemble, float32(30))
 }
+
+func TestWebcamsToInfo(t *testing.T) {
+	webcams := []*pb.Webcam{
+		{
+			Name:   "some name",
+			Id:     "some id",
+			Label:  "some label",
+			Status: "some state",
+			Properties: []*pb.Property{
+				{WidthPx: 320, HeightPx: 240, FrameFormat: "some format", FrameRate: 30.0},
+				{WidthPx: 640, HeightPx: 480, FrameFormat: "another format", FrameRate: 15.0},
+			},
+		},
+	}
+
+	infos := videosource.WebcamsToInfo(webcams)
+	test.That(t, infos, test.ShouldHaveLength, 1)
+	test.That(t, infos[0].Name, test.ShouldEqual, "some name")
+	test.That(t, infos[0].ID, test.ShouldEqual, "some id")
+	test.That(t, infos[0].Label, test.ShouldEqual, "some label")
+	test.That(t, infos[0].Status, test.ShouldEqual, "some state")
+	test.That(t, infos[0].Properties, test.ShouldResemble, []videosource.Property{
+		{Width: 320, Height: 240, Format: "some format", FrameRate: 30.0},
+		{Width: 640, Height: 480, Format: "another format", FrameRate: 15.0},
+	})
+}
+
+func TestResolveWebcamPath(t *testing.T) {
+	getDrivers := func() []driver.Driver {
+		return []driver.Driver{
+			newFakeDriver("video0;usb-cam-a", nil),
+			newFakeDriver("video2;usb-cam-b", nil),
+			newFakeDriver("video4;usb-cam-b", nil),
+		}
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		_, err := videosource.ResolveWebcamPath("/dev/video9", getDrivers)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "no webcam found")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "video0")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "video2")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "video4")
+	})
+
+	t.Run("unique match", func(t *testing.T) {
+		label, err := videosource.ResolveWebcamPath("/dev/video0", getDrivers)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, label, test.ShouldEqual, "video0")
+
+		label, err = videosource.ResolveWebcamPath("usb-cam-a", getDrivers)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, label, test.ShouldEqual, "video0")
+	})
+
+	t.Run("ambiguous match", func(t *testing.T) {
+		_, err := videosource.ResolveWebcamPath("usb-cam-b", getDrivers)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "matches multiple webcams")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "video2")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "video4")
+	})
+}
+
+func TestKnownIntrinsics(t *testing.T) {
+	// linux drivers report their name as "<model>;<bus info>"
+	intrinsics, model, ok := videosource.KnownIntrinsics(driver.Info{
+		Name:  "C270 HD WEBCAM;usb-0000:01:00.0-1.2",
+		Label: "video0;video0",
+	})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, model, test.ShouldEqual, "C270 HD WEBCAM")
+	test.That(t, intrinsics.Width, test.ShouldEqual, 640)
+	test.That(t, intrinsics.Height, test.ShouldEqual, 480)
+	test.That(t, intrinsics.Fx, test.ShouldAlmostEqual, 911.4644040206691)
+	test.That(t, intrinsics.Fy, test.ShouldAlmostEqual, 912.7619272761438)
+
+	_, _, ok = videosource.KnownIntrinsics(driver.Info{Name: "unknown camera;usb-1", Label: "video2;video2"})
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+func TestWebcamImagesCapturedAt(t *testing.T) {
+	src := gostream.NewVideoSource(gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+		return image.NewRGBA(image.Rect(0, 0, 4, 4)), func() {}, nil
+	}), prop.Video{})
+	cam, err := videosource.NewWebcamFromSource(context.Background(), camera.Named("webcam"), src, videosource.WebcamConfig{})
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, cam.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	var last time.Time
+	for i := 0; i < 10; i++ {
+		imgs, meta, err := cam.Images(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, imgs, test.ShouldHaveLength, 1)
+		test.That(t, meta.CapturedAt.After(last), test.ShouldBeTrue)
+		last = meta.CapturedAt
+	}
+}
+
+func TestWebcamReconnectPolicy(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		conf := videosource.WebcamConfig{Reconnect: &videosource.ReconnectConfig{MaxAttempts: -1}}
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		conf = videosource.WebcamConfig{Reconnect: &videosource.ReconnectConfig{Multiplier: 0.5}}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		conf = videosource.WebcamConfig{Reconnect: &videosource.ReconnectConfig{InitialDelayMs: 100, MaxDelayMs: 10}}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		conf = videosource.WebcamConfig{Reconnect: &videosource.ReconnectConfig{InitialDelayMs: 10, MaxDelayMs: 100, Multiplier: 2}}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		conf := &videosource.ReconnectConfig{InitialDelayMs: 1, MaxDelayMs: 4, Multiplier: 2, MaxAttempts: 5}
+		var attempts []time.Time
+		// the device never comes back
+		err := videosource.Reconnect(context.Background(), conf, func() error {
+			attempts = append(attempts, time.Now())
+			return errors.New("no device")
+		})
+		test.That(t, err, test.ShouldBeError)
+		test.That(t, errors.Is(err, videosource.ErrReconnectAttemptsExceeded), test.ShouldBeTrue)
+		test.That(t, attempts, test.ShouldHaveLength, 5)
+		// delays of 1, 2, 4, 4, 4 ms have elapsed
+		test.That(t, attempts[4].Sub(attempts[0]), test.ShouldBeGreaterThanOrEqualTo, 10*time.Millisecond)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var attempts int
+		err := videosource.Reconnect(ctx, &videosource.ReconnectConfig{InitialDelayMs: 1}, func() error {
+			attempts++
+			if attempts == 3 {
+				cancel()
+			}
+			return errors.New("no device")
+		})
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+		test.That(t, attempts, test.ShouldEqual, 3)
+	})
+
+	t.Run("succeeds when the device returns", func(t *testing.T) {
+		var attempts int
+		err := videosource.Reconnect(context.Background(), &videosource.ReconnectConfig{InitialDelayMs: 1, MaxAttempts: 5}, func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("no device")
+			}
+			return nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, attempts, test.ShouldEqual, 3)
+	})
+}
+
+func TestWebcamStreamResolution(t *testing.T) {
+	ctx := context.Background()
+	newSource := func() gostream.VideoSource {
+		return gostream.NewVideoSource(gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+			return image.NewRGBA(image.Rect(0, 0, 1280, 720)), func() {}, nil
+		}), prop.Video{Width: 1280, Height: 720})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		conf := videosource.WebcamConfig{StreamWidth: -1}
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		conf = videosource.WebcamConfig{Width: 640, Height: 480, StreamWidth: 1280, StreamHeight: 720}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		conf = videosource.WebcamConfig{Width: 1280, Height: 720, StreamWidth: 640, StreamHeight: 360}
+		_, err = conf.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("larger than the device resolution", func(t *testing.T) {
+		src := newSource()
+		defer func() {
+			test.That(t, src.Close(ctx), test.ShouldBeNil)
+		}()
+		conf := videosource.WebcamConfig{StreamWidth: 1920, StreamHeight: 1080}
+		_, err := videosource.NewWebcamFromSource(ctx, camera.Named("webcam"), src, conf)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "larger than the resolution")
+	})
+
+	t.Run("images and stream resolutions differ", func(t *testing.T) {
+		conf := videosource.WebcamConfig{Width: 1280, Height: 720, StreamWidth: 640, StreamHeight: 360}
+		cam, err := videosource.NewWebcamFromSource(ctx, camera.Named("webcam"), newSource(), conf)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, cam.Close(ctx), test.ShouldBeNil)
+		}()
+
+		imgs, _, err := cam.Images(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, imgs, test.ShouldHaveLength, 1)
+		test.That(t, imgs[0].Image.Bounds().Dx(), test.ShouldEqual, 1280)
+		test.That(t, imgs[0].Image.Bounds().Dy(), test.ShouldEqual, 720)
+
+		stream, err := cam.Stream(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		img, release, err := stream.Next(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		release()
+		test.That(t, img.Bounds().Dx(), test.ShouldEqual, 640)
+		test.That(t, img.Bounds().Dy(), test.ShouldEqual, 360)
+		test.That(t, stream.Close(ctx), test.ShouldBeNil)
+	})
+}
+
+func TestWebcamFailedReconfigure(t *testing.T) {
+	ctx := context.Background()
+	newSource := func(width, height int) gostream.VideoSource {
+		return gostream.NewVideoSource(gostream.VideoReaderFunc(func(ctx context.Context) (image.Image, func(), error) {
+			return image.NewRGBA(image.Rect(0, 0, width, height)), func() {}, nil
+		}), prop.Video{Width: width, Height: height})
+	}
+	conf := videosource.WebcamConfig{Path: "video0"}
+	cam, err := videosource.NewWebcamFromSource(ctx, camera.Named("webcam"), newSource(1280, 720), conf)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, cam.Close(ctx), test.ShouldBeNil)
+	}()
+	var opened []string
+	videosource.SetWebcamSourceFinder(cam, func(conf videosource.WebcamConfig) (gostream.VideoSource, error) {
+		opened = append(opened, conf.Path)
+		if conf.Path == "video1" {
+			return newSource(640, 480), nil
+		}
+		return newSource(1280, 720), nil
+	})
+
+	// the new device can't be streamed at the configured resolution
+	newConf := &videosource.WebcamConfig{Path: "video1", StreamWidth: 1280, StreamHeight: 720}
+	err = cam.Reconfigure(ctx, nil, resource.Config{ConvertedAttributes: newConf})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "larger than the resolution")
+
+	// so the camera is reopened with its previous config
+	test.That(t, opened, test.ShouldResemble, []string{"video1", "video0"})
+	imgs, _, err := cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, imgs, test.ShouldHaveLength, 1)
+	test.That(t, imgs[0].Image.Bounds().Dx(), test.ShouldEqual, 1280)
+
+	// which is still its config, so reconfiguring with it doesn't reopen the camera
+	err = cam.Reconfigure(ctx, nil, resource.Config{ConvertedAttributes: &conf})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opened, test.ShouldHaveLength, 2)
+}
@@ -55,6 +55,8 @@ type Collector interface {
 	Close()
 	Collect()
 	Flush()
+	// DroppedCaptures returns the number of captures dropped because the target applied backpressure.
+	DroppedCaptures() int64
 }
 
 type collector struct {
@@ -75,6 +77,9 @@ type collector struct {
 	closeFinished    bool
 	target           datacapture.BufferedWriter
 	lastLoggedErrors map[string]int64
+	// targetBusy is set when the target rejects a write with ErrBufferBusy or ErrBufferFull.
+	targetBusy      atomic.Bool
+	droppedCaptures atomic.Int64
 }
 
 // Close closes the channels backing the Collector. It should always be called before disposing of a Collector to avoid
@@ -99,6 +104,10 @@ func (c *collector) Close() {
 	if err := c.target.Flush(); err != nil {
 		c.logger.Errorw("failed to flush capture data", "error", err)
 	}
+	if dropped := c.DroppedCaptures(); dropped > 0 {
+		c.logger.Warnw("collector dropped captures because its target was busy or full",
+			"target", c.target.Path(), "dropped_captures", dropped)
+	}
 
 	close(c.captureErrors)
 	c.logRoutine.Wait()
@@ -107,6 +116,11 @@ func (c *collector) Close() {
 	c.closeFinished = true
 }
 
+// DroppedCaptures returns the number of captures dropped because the target applied backpressure.
+func (c *collector) DroppedCaptures() int64 {
+	return c.droppedCaptures.Load()
+}
+
 func (c *collector) Flush() {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -215,6 +229,13 @@ func (c *collector) tickerBasedCapture(started chan struct{}) {
 }
 
 func (c *collector) getAndPushNextReading() {
+	// If the target rejected the last write, skip this interval rather than capturing a reading
+	// that is likely to be dropped. The following interval captures again to probe the target.
+	if c.targetBusy.CompareAndSwap(true, false) {
+		c.droppedCaptures.Add(1)
+		return
+	}
+
 	timeRequested := timestamppb.New(c.clock.Now().UTC())
 	reading, err := c.captureFunc(c.cancelCtx, c.params)
 	timeReceived := timestamppb.New(c.clock.Now().UTC())
@@ -310,10 +331,28 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 }
 
 func (c *collector) writeCaptureResults() error {
+	// droppedBefore is the number of captures dropped before the target last started rejecting writes, or
+	// -1 if the last write was accepted.
+	droppedBefore := int64(-1)
 	for msg := range c.captureResults {
 		if err := c.target.Write(msg); err != nil {
+			if errors.Is(err, datacapture.ErrBufferBusy) || errors.Is(err, datacapture.ErrBufferFull) {
+				if droppedBefore < 0 {
+					droppedBefore = c.droppedCaptures.Load()
+					c.logger.Warnw("capture target is rejecting writes, dropping captures until it accepts them again",
+						"target", c.target.Path(), "error", err)
+				}
+				c.targetBusy.Store(true)
+				c.droppedCaptures.Add(1)
+				continue
+			}
 			return err
 		}
+		if droppedBefore >= 0 {
+			c.logger.Infow("capture target is accepting writes again",
+				"target", c.target.Path(), "dropped_captures", c.droppedCaptures.Load()-droppedBefore)
+			droppedBefore = -1
+		}
 	}
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"go.viam.com/utils/testutils"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -327,6 +329,66 @@ func TestLogErrorsOnlyOnce(t *testing.T) {
 	c.Close()
 }
 
+func TestBusyTargetSkipsCaptures(t *testing.T) {
+	logger, logs := logging.NewObservedTestLogger(t)
+	wrote := make(chan struct{}, 1)
+	target := &busyBuffer{wrote: wrote}
+	var captures atomic.Int64
+	capturer := CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		captures.Add(1)
+		return dummyStructReading, nil
+	})
+	mockClock := clock.NewMock()
+	interval := time.Millisecond * 5
+
+	params := CollectorParams{
+		ComponentName: "testComponent",
+		Interval:      interval,
+		MethodParams:  map[string]*anypb.Any{"name": fakeVal},
+		Target:        target,
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        logger,
+		Clock:         mockClock,
+	}
+	c, err := NewCollector(capturer, params)
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	defer c.Close()
+
+	// The first capture is written and rejected as the target is busy.
+	mockClock.Add(interval)
+	<-wrote
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, c.DroppedCaptures(), test.ShouldEqual, 1)
+	})
+
+	// The next interval is skipped without capturing.
+	mockClock.Add(interval)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, c.DroppedCaptures(), test.ShouldEqual, 2)
+	})
+	test.That(t, captures.Load(), test.ShouldEqual, 1)
+
+	// The one after that captures again to check whether the target is still busy.
+	mockClock.Add(interval)
+	<-wrote
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, c.DroppedCaptures(), test.ShouldEqual, 3)
+	})
+	test.That(t, captures.Load(), test.ShouldEqual, 2)
+
+	// The target rejecting writes is logged once, and the number of dropped captures when the collector closes.
+	test.That(t, logs.FilterMessageSnippet("rejecting writes").Len(), test.ShouldEqual, 1)
+	c.Close()
+	dropped := logs.FilterMessageSnippet("dropped captures").All()
+	test.That(t, len(dropped), test.ShouldEqual, 1)
+	test.That(t, dropped[0].ContextMap()["dropped_captures"], test.ShouldEqual, 3)
+}
+
 func validateReadings(t *testing.T, act []*v1.SensorData, n int) {
 	t.Helper()
 	for i := 0; i < n; i++ {
@@ -373,3 +435,20 @@ func (b *signalingBuffer) Flush() error {
 func (b *signalingBuffer) Path() string {
 	return b.bw.Path()
 }
+
+type busyBuffer struct {
+	wrote chan struct{}
+}
+
+func (b *busyBuffer) Write(data *v1.SensorData) error {
+	b.wrote <- struct{}{}
+	return datacapture.ErrBufferBusy
+}
+
+func (b *busyBuffer) Flush() error {
+	return nil
+}
+
+func (b *busyBuffer) Path() string {
+	return ""
+}
@@ -4,50 +4,87 @@ package x264
 import (
 	"context"
 	"image"
+	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/pion/mediadevices/pkg/codec"
 	"github.com/pion/mediadevices/pkg/codec/x264"
 	"github.com/pion/mediadevices/pkg/prop"
+	"github.com/pkg/errors"
 
 	ourcodec "go.viam.com/rdk/gostream/codec"
 )
 
 type encoder struct {
-	codec  codec.ReadCloser
-	img    image.Image
-	logger golog.Logger
+	// mu guards codec, bitrate & lastBuilt, as the codec may be reconfigured while the stream goroutine is encoding
+	mu                 sync.Mutex
+	codec              codec.ReadCloser
+	bitrate            int
+	lastBuilt          time.Time
+	minRebuildInterval time.Duration
+	width              int
+	height             int
+	keyFrameInterval   int
+	img                image.Image
+	logger             golog.Logger
 }
 
 // Gives suitable results. Probably want to make this configurable this in the future.
 const bitrate = 3_200_000
 
+// The range SetBitrate clamps the bitrate to.
+const (
+	minBitrate = 100_000
+	maxBitrate = 10_000_000
+)
+
+// As changing the bitrate rebuilds the codec & so produces a key frame, SetBitrate ignores changes of
+// less than 1/bitrateHysteresis of the current bitrate and rebuilds at most once per minRebuildInterval.
+const (
+	bitrateHysteresis  = 5
+	minRebuildInterval = 2 * time.Second
+)
+
 // NewEncoder returns an x264 encoder that can encode images of the given width and height. It will
 // also ensure that it produces key frames at the given interval.
 func NewEncoder(width, height, keyFrameInterval int, logger golog.Logger) (ourcodec.VideoEncoder, error) {
-	enc := &encoder{logger: logger}
+	enc := &encoder{
+		bitrate:            bitrate,
+		minRebuildInterval: minRebuildInterval,
+		width:              width,
+		height:             height,
+		keyFrameInterval:   keyFrameInterval,
+		logger:             logger,
+	}
+
+	codec, err := enc.buildCodec()
+	if err != nil {
+		return nil, err
+	}
+	enc.codec = codec
+	enc.lastBuilt = time.Now()
 
+	return enc, nil
+}
+
+// buildCodec builds an x264 codec encoding at v's bitrate.
+func (v *encoder) buildCodec() (codec.ReadCloser, error) {
 	var builder codec.VideoEncoderBuilder
 	params, err := x264.NewParams()
 	if err != nil {
 		return nil, err
 	}
 	builder = &params
-	params.BitRate = bitrate
-	params.KeyFrameInterval = keyFrameInterval
+	params.BitRate = v.bitrate
+	params.KeyFrameInterval = v.keyFrameInterval
 
-	codec, err := builder.BuildVideoEncoder(enc, prop.Media{
+	return builder.BuildVideoEncoder(v, prop.Media{
 		Video: prop.Video{
-			Width:  width,
-			Height: height,
+			Width:  v.width,
+			Height: v.height,
 		},
 	})
-	if err != nil {
-		return nil, err
-	}
-	enc.codec = codec
-
-	return enc, nil
 }
 
 // Read returns an image for codec to process.
@@ -57,6 +94,8 @@ func (v *encoder) Read() (img image.Image, release func(), err error) {
 
 // Encode asks the codec to process the given image.
 func (v *encoder) Encode(_ context.Context, img image.Image) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	v.img = img
 	data, release, err := v.codec.Read()
 	dataCopy := make([]byte, len(data))
@@ -65,7 +104,74 @@ func (v *encoder) Encode(_ context.Context, img image.Image) ([]byte, error) {
 	return dataCopy, err
 }
 
+// SetBitrate sets the target bitrate in bits per second of subsequent Encode calls, e.g. in response to
+// congestion feedback, clamped between minBitrate and maxBitrate. It is safe to call while another
+// goroutine is calling Encode.
+//
+// As x264 can't change the bitrate of a running codec, the codec is rebuilt at the new bitrate, which
+// costs building a new encoder & makes the next encoded frame a key frame, several times the size of
+// the frames between them. To keep frequent feedback from flooding the stream with key frames, a
+// change within 1/bitrateHysteresis of the current bitrate, or requested within minRebuildInterval of
+// the codec last being built, is ignored; callers are expected to keep reporting the bitrate they want.
+func (v *encoder) SetBitrate(bitrate int) error {
+	if bitrate < minBitrate {
+		bitrate = minBitrate
+	} else if bitrate > maxBitrate {
+		bitrate = maxBitrate
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if bitrate == v.bitrate {
+		return nil
+	}
+	if controller, ok := v.codec.Controller().(codec.BitRateController); ok {
+		if err := controller.SetBitRate(bitrate); err != nil {
+			return err
+		}
+		v.bitrate = bitrate
+		return nil
+	}
+
+	diff := bitrate - v.bitrate
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff < v.bitrate/bitrateHysteresis || time.Since(v.lastBuilt) < v.minRebuildInterval {
+		return nil
+	}
+
+	prevBitrate := v.bitrate
+	v.bitrate = bitrate
+	newCodec, err := v.buildCodec()
+	if err != nil {
+		v.bitrate = prevBitrate
+		return err
+	}
+	if err := v.codec.Close(); err != nil {
+		v.logger.Warnw("error closing x264 codec after changing the bitrate", "error", err)
+	}
+	v.codec = newCodec
+	v.lastBuilt = time.Now()
+	return nil
+}
+
+// ForceKeyFrame makes the next Encode produce a key frame regardless of the key frame interval, e.g. so
+// that a viewer joining the stream doesn't wait for the next periodic key frame. It is safe to call
+// while another goroutine is calling Encode.
+func (v *encoder) ForceKeyFrame() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	controller, ok := v.codec.Controller().(codec.KeyFrameController)
+	if !ok {
+		return errors.New("x264 codec does not support forcing key frames")
+	}
+	return controller.ForceKeyFrame()
+}
+
 // Close closes the encoder.
 func (v *encoder) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	return v.codec.Close()
 }
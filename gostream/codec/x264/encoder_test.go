@@ -7,18 +7,21 @@ import (
 	"image/color"
 	"image/jpeg"
 	"image/png"
+	"math/rand"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/nfnt/resize"
 	"go.viam.com/test"
+
+	ourcodec "go.viam.com/rdk/gostream/codec"
 )
 
 const (
-	DefaultKeyFrameInterval = 30
-	Width                   = 640
-	Height                  = 480
+	Width  = 640
+	Height = 480
 )
 
 func pngToImage(b *testing.B, loc string) (image.Image, error) {
@@ -59,7 +62,7 @@ func BenchmarkEncodeRGBA(b *testing.B) {
 	imgCyan := getResizedImageFromFile(b, "../../data/cyan.png")
 	imgFuchsia := getResizedImageFromFile(b, "../../data/fuchsia.png")
 	ctx := context.Background()
-	encoder, err := NewEncoder(Width, Height, DefaultKeyFrameInterval, logger)
+	encoder, err := NewEncoder(Width, Height, ourcodec.DefaultKeyFrameInterval, logger)
 	test.That(b, err, test.ShouldBeNil)
 
 	b.ResetTimer()
@@ -87,7 +90,7 @@ func BenchmarkEncodeYCbCr(b *testing.B) {
 	imgCY, err := convertToYCbCr(b, imgCyan)
 	test.That(b, err, test.ShouldBeNil)
 
-	encoder, err := NewEncoder(Width, Height, DefaultKeyFrameInterval, logger)
+	encoder, err := NewEncoder(Width, Height, ourcodec.DefaultKeyFrameInterval, logger)
 	test.That(b, err, test.ShouldBeNil)
 
 	ctx := context.Background()
@@ -103,3 +106,111 @@ func BenchmarkEncodeYCbCr(b *testing.B) {
 		w = !w
 	}
 }
+
+func noiseImage(rnd *rand.Rand) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	rnd.Read(img.Pix)
+	return img
+}
+
+// encodedSize returns the total size of encoding numFrames noise images.
+func encodedSize(t *testing.T, enc *encoder, rnd *rand.Rand, numFrames int) int {
+	t.Helper()
+	var size int
+	for i := 0; i < numFrames; i++ {
+		data, err := enc.Encode(context.Background(), noiseImage(rnd))
+		test.That(t, err, test.ShouldBeNil)
+		size += len(data)
+	}
+	return size
+}
+
+func TestSetBitrate(t *testing.T) {
+	videoEncoder, err := NewEncoder(Width, Height, ourcodec.DefaultKeyFrameInterval, golog.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer videoEncoder.Close()
+	enc, ok := videoEncoder.(*encoder)
+	test.That(t, ok, test.ShouldBeTrue)
+	enc.minRebuildInterval = 0
+
+	t.Run("the bitrate is clamped", func(t *testing.T) {
+		test.That(t, enc.SetBitrate(1), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, minBitrate)
+		test.That(t, enc.SetBitrate(1_000_000_000), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, maxBitrate)
+	})
+
+	t.Run("subsequent encodes use the new bitrate", func(t *testing.T) {
+		rnd := rand.New(rand.NewSource(0))
+		numFrames := ourcodec.DefaultKeyFrameInterval
+		test.That(t, enc.SetBitrate(maxBitrate), test.ShouldBeNil)
+		highBitrateSize := encodedSize(t, enc, rnd, numFrames)
+
+		test.That(t, enc.SetBitrate(minBitrate), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, minBitrate)
+		lowBitrateSize := encodedSize(t, enc, rnd, numFrames)
+		test.That(t, lowBitrateSize, test.ShouldBeLessThan, highBitrateSize/2)
+	})
+
+	t.Run("small changes are ignored", func(t *testing.T) {
+		test.That(t, enc.SetBitrate(bitrate), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, bitrate)
+		test.That(t, enc.SetBitrate(bitrate+bitrate/10), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, bitrate)
+		test.That(t, enc.SetBitrate(bitrate-bitrate/10), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, bitrate)
+	})
+
+	t.Run("rebuilds are rate limited", func(t *testing.T) {
+		enc.minRebuildInterval = time.Hour
+		test.That(t, enc.SetBitrate(minBitrate), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, bitrate)
+
+		enc.lastBuilt = time.Now().Add(-time.Hour)
+		test.That(t, enc.SetBitrate(minBitrate), test.ShouldBeNil)
+		test.That(t, enc.bitrate, test.ShouldEqual, minBitrate)
+	})
+}
+
+// isKeyFrame returns whether the Annex B encoded frame contains an IDR slice.
+func isKeyFrame(frame []byte) bool {
+	for i := 0; i+3 < len(frame); i++ {
+		if frame[i] == 0 && frame[i+1] == 0 && frame[i+2] == 1 && frame[i+3]&0x1F == 5 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestForceKeyFrame(t *testing.T) {
+	videoEncoder, err := NewEncoder(Width, Height, ourcodec.DefaultKeyFrameInterval, golog.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	defer videoEncoder.Close()
+	enc, ok := videoEncoder.(*encoder)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// a slowly moving gradient, as abrupt changes between frames may produce scene cut key frames
+	var frameNum int
+	encode := func() []byte {
+		img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+		for x := 0; x < Width; x++ {
+			for y := 0; y < Height; y++ {
+				img.Set(x, y, color.RGBA{R: uint8(x + frameNum), G: uint8(y), B: 128, A: 255})
+			}
+		}
+		frameNum++
+		data, err := enc.Encode(context.Background(), img)
+		test.That(t, err, test.ShouldBeNil)
+		return data
+	}
+
+	// only the first of these frames is a periodic key frame
+	test.That(t, isKeyFrame(encode()), test.ShouldBeTrue)
+	for i := 0; i < ourcodec.DefaultKeyFrameInterval/2; i++ {
+		test.That(t, isKeyFrame(encode()), test.ShouldBeFalse)
+	}
+
+	test.That(t, enc.ForceKeyFrame(), test.ShouldBeNil)
+	test.That(t, isKeyFrame(encode()), test.ShouldBeTrue)
+	test.That(t, isKeyFrame(encode()), test.ShouldBeFalse)
+}
@@ -71,12 +71,22 @@ func NewStream(config StreamConfig) (Stream, error) {
 	if config.TargetFrameRate == 0 {
 		config.TargetFrameRate = codec.DefaultKeyFrameInterval
 	}
+	if config.KeyFrameInterval < 0 {
+		return nil, errors.New("key frame interval must not be negative")
+	}
+	if config.KeyFrameInterval == 0 {
+		// one key frame per second
+		config.KeyFrameInterval = config.TargetFrameRate
+	}
 
 	name := config.Name
 	if name == "" {
 		name = uuid.NewString()
 	}
 
+	// The audio & video tracks share the stream's name as their stream ID and stamp their RTP timestamps at
+	// the clock rate negotiated for their codec, so a viewer can synchronize them using the RTCP sender reports
+	// the peer connection sends for each track.
 	var trackLocal *trackLocalStaticSample
 	if config.VideoEncoderFactory != nil {
 		trackLocal = newVideoTrackLocalStaticSample(
@@ -403,7 +413,7 @@ func (bs *basicStream) processOutputAudioChunks() {
 
 func (bs *basicStream) initVideoCodec(width, height int) error {
 	var err error
-	bs.videoEncoder, err = bs.config.VideoEncoderFactory.New(width, height, bs.config.TargetFrameRate, bs.logger)
+	bs.videoEncoder, err = bs.config.VideoEncoderFactory.New(width, height, bs.config.KeyFrameInterval, bs.logger)
 	return err
 }
 
@@ -15,5 +15,10 @@ type StreamConfig struct {
 	// TargetFrameRate will hint to the stream to try to maintain this frame rate.
 	TargetFrameRate int
 
+	// KeyFrameInterval is the number of frames between the key frames the video encoder produces. More
+	// frequent key frames let viewers start & recover sooner at the cost of bandwidth.
+	// Defaults to TargetFrameRate, i.e. one key frame per second.
+	KeyFrameInterval int
+
 	Logger golog.Logger
 }
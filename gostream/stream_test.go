@@ -2,13 +2,19 @@ package gostream
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"image"
 	"testing"
 	"time"
 
+	"github.com/edaniels/golog"
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v3"
 	"go.viam.com/test"
 	"golang.org/x/time/rate"
+
+	"go.viam.com/rdk/gostream/codec"
 )
 
 func init() {
@@ -159,3 +165,131 @@ func BenchmarkStream_30FPS_3Streams(b *testing.B) {
 	cancel()
 	b.ReportMetric(SecondNs/avgNs, "fps")
 }
+
+type mimeTypeVideoEncoderFactory string
+
+func (f mimeTypeVideoEncoderFactory) New(height, width, keyFrameInterval int, logger golog.Logger) (codec.VideoEncoder, error) {
+	return nil, nil
+}
+
+func (f mimeTypeVideoEncoderFactory) MIMEType() string {
+	return string(f)
+}
+
+type mimeTypeAudioEncoderFactory string
+
+func (f mimeTypeAudioEncoderFactory) New(
+	sampleRate, channelCount int,
+	latency time.Duration,
+	logger golog.Logger,
+) (codec.AudioEncoder, error) {
+	return nil, nil
+}
+
+func (f mimeTypeAudioEncoderFactory) MIMEType() string {
+	return string(f)
+}
+
+func TestStreamTrackSyncMetadata(t *testing.T) {
+	s, err := NewStream(StreamConfig{
+		Name:                "cam",
+		VideoEncoderFactory: mimeTypeVideoEncoderFactory(webrtc.MimeTypeH264),
+		AudioEncoderFactory: mimeTypeAudioEncoderFactory(webrtc.MimeTypeOpus),
+	})
+	test.That(t, err, test.ShouldBeNil)
+	defer s.Stop()
+
+	videoTrack, ok := s.(internalStream).VideoTrackLocal()
+	test.That(t, ok, test.ShouldBeTrue)
+	audioTrack, ok := s.(internalStream).AudioTrackLocal()
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// both tracks are part of the same stream so that viewers synchronize them
+	test.That(t, videoTrack.StreamID(), test.ShouldEqual, "cam")
+	test.That(t, audioTrack.StreamID(), test.ShouldEqual, videoTrack.StreamID())
+
+	// their RTP timestamps advance at the clock rate negotiated with the viewer, so it can put them on one timeline
+	audioSample := audioTrack.(*trackLocalStaticSample)
+	audioSample.setAudioLatency(20 * time.Millisecond)
+	writer := &rtpHeaderWriter{}
+	_, err = audioSample.Bind(&fakeTrackLocalContext{
+		codecs: []webrtc.RTPCodecParameters{{
+			RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeOpus, ClockRate: 48000, Channels: 2},
+			PayloadType:        111,
+		}},
+		writer: writer,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, audioSample.WriteData([]byte{1}), test.ShouldBeNil)
+	test.That(t, audioSample.WriteData([]byte{2}), test.ShouldBeNil)
+	test.That(t, writer.headers, test.ShouldHaveLength, 2)
+	test.That(t, writer.headers[0].PayloadType, test.ShouldEqual, 111)
+	test.That(t, writer.headers[1].Timestamp-writer.headers[0].Timestamp, test.ShouldEqual, 960)
+}
+
+type fakeTrackLocalContext struct {
+	webrtc.TrackLocalContext
+	codecs []webrtc.RTPCodecParameters
+	writer webrtc.TrackLocalWriter
+}
+
+func (c *fakeTrackLocalContext) CodecParameters() []webrtc.RTPCodecParameters { return c.codecs }
+
+func (c *fakeTrackLocalContext) SSRC() webrtc.SSRC { return 1 }
+
+func (c *fakeTrackLocalContext) WriteStream() webrtc.TrackLocalWriter { return c.writer }
+
+func (c *fakeTrackLocalContext) ID() string { return "fake" }
+
+type rtpHeaderWriter struct {
+	headers []rtp.Header
+}
+
+func (w *rtpHeaderWriter) WriteRTP(header *rtp.Header, payload []byte) (int, error) {
+	w.headers = append(w.headers, *header)
+	return len(payload), nil
+}
+
+func (w *rtpHeaderWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+type keyFrameIntervalVideoEncoderFactory struct {
+	mimeTypeVideoEncoderFactory
+	keyFrameInterval int
+}
+
+func (f *keyFrameIntervalVideoEncoderFactory) New(height, width, keyFrameInterval int, logger golog.Logger) (codec.VideoEncoder, error) {
+	f.keyFrameInterval = keyFrameInterval
+	return nil, nil
+}
+
+func TestStreamKeyFrameInterval(t *testing.T) {
+	for _, tc := range []struct {
+		targetFrameRate     int
+		keyFrameInterval    int
+		expKeyFrameInterval int
+	}{
+		{targetFrameRate: 60, keyFrameInterval: 0, expKeyFrameInterval: 60},
+		{targetFrameRate: 0, keyFrameInterval: 0, expKeyFrameInterval: codec.DefaultKeyFrameInterval},
+		{targetFrameRate: 60, keyFrameInterval: 5, expKeyFrameInterval: 5},
+		{targetFrameRate: 60, keyFrameInterval: 120, expKeyFrameInterval: 120},
+	} {
+		factory := &keyFrameIntervalVideoEncoderFactory{mimeTypeVideoEncoderFactory: webrtc.MimeTypeH264}
+		s, err := NewStream(StreamConfig{
+			VideoEncoderFactory: factory,
+			TargetFrameRate:     tc.targetFrameRate,
+			KeyFrameInterval:    tc.keyFrameInterval,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, s.(*basicStream).initVideoCodec(640, 480), test.ShouldBeNil)
+		test.That(t, factory.keyFrameInterval, test.ShouldEqual, tc.expKeyFrameInterval)
+		s.Stop()
+	}
+
+	_, err := NewStream(StreamConfig{
+		VideoEncoderFactory: mimeTypeVideoEncoderFactory(webrtc.MimeTypeH264),
+		KeyFrameInterval:    -1,
+	})
+	test.That(t, err, test.ShouldBeError, errors.New("key frame interval must not be negative"))
+}
@@ -10,6 +10,7 @@ import (
 // APIResourceCollection defines a collection of typed resources.
 type APIResourceCollection[T Resource] interface {
 	Resource(name string) (T, error)
+	Names() []Name
 	ReplaceAll(resources map[Name]T) error
 	Add(resName Name, res T) error
 	Remove(name Name) error
@@ -57,6 +58,17 @@ func (s *apiResourceCollection[T]) Resource(name string) (T, error) {
 	return zero, NewNotFoundError(NewName(s.api, name))
 }
 
+// Names returns the names of all resources in the collection.
+func (s *apiResourceCollection[T]) Names() []Name {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]Name, 0, len(s.resources))
+	for name := range s.resources {
+		names = append(names, NewName(s.api, name))
+	}
+	return names
+}
+
 // ReplaceAll replaces all resources with r.
 func (s *apiResourceCollection[T]) ReplaceAll(r map[Name]T) error {
 	s.mu.Lock()
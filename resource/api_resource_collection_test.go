@@ -33,9 +33,13 @@ func TestAPIResourceCollection(t *testing.T) {
 	res, err = svc.Resource(res2.Name().ShortName())
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, res, test.ShouldResemble, res2)
+	test.That(t, svc.Names(), test.ShouldHaveLength, 2)
+	test.That(t, svc.Names(), test.ShouldContain, res1.Name())
+	test.That(t, svc.Names(), test.ShouldContain, res2.Name())
 
 	err = svc.ReplaceAll(map[resource.Name]resource.Resource{})
 	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.Names(), test.ShouldBeEmpty)
 	_, err = svc.Resource(res1.Name().ShortName())
 	test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(res1.Name()))
 	_, err = svc.Resource(res2.Name().ShortName())
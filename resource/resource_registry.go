@@ -436,6 +436,10 @@ func (g genericSubypeCollection[ResourceT]) Resource(name string) (Resource, err
 	return g.typed.Resource(name)
 }
 
+func (g genericSubypeCollection[ResourceT]) Names() []Name {
+	return g.typed.Names()
+}
+
 func (g genericSubypeCollection[ResourceT]) ReplaceAll(resources map[Name]Resource) error {
 	if len(resources) == 0 {
 		return nil
@@ -3,6 +3,7 @@ package webstream
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/edaniels/golog"
@@ -295,6 +296,35 @@ func (ss *Server) RemoveStream(ctx context.Context, req *streampb.RemoveStreamRe
 	return &streampb.RemoveStreamResponse{}, nil
 }
 
+// CloseStream closes and unregisters the stream with the given name, removing its tracks from
+// any peers it is being sent to. Other streams are left untouched.
+func (ss *Server) CloseStream(name string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	streamState, ok := ss.nameToStreamState[name]
+	if !ok {
+		return fmt.Errorf("no stream for %q", name)
+	}
+
+	var errs error
+	for pc, peerStreams := range ss.activePeerStreams {
+		ps, ok := peerStreams[name]
+		if !ok {
+			continue
+		}
+		for _, sender := range ps.senders {
+			errs = multierr.Combine(errs, pc.RemoveTrack(sender))
+		}
+		delete(peerStreams, name)
+	}
+	errs = multierr.Combine(errs, streamState.Close())
+
+	delete(ss.nameToStreamState, name)
+	ss.streamNames = slices.DeleteFunc(ss.streamNames, func(n string) bool { return n == name })
+	return errs
+}
+
 // Close closes the Server and waits for spun off goroutines to complete.
 func (ss *Server) Close() error {
 	ss.mu.Lock()
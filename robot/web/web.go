@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -205,6 +206,9 @@ func (svc *webService) Start(ctx context.Context, o weboptions.Options) error {
 	if svc.isRunning {
 		return errors.New("web server already started")
 	}
+	if err := svc.opts.validate(); err != nil {
+		return errors.Wrap(err, "invalid web service options")
+	}
 	svc.isRunning = true
 	cancelCtx, cancelFunc := context.WithCancel(ctx)
 
@@ -262,6 +266,56 @@ func (svc *webService) ModuleAddress() string {
 	return svc.modAddr
 }
 
+// ListRegisteredAPIsCommand is the DoCommand command which lists the APIs registered with the web
+// service along with the names of the resources being served for each.
+const ListRegisteredAPIsCommand = "list_registered_apis"
+
+// DoCommand supports the ListRegisteredAPIsCommand command.
+func (svc *webService) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd["command"]
+	if !ok {
+		return nil, errors.New("missing 'command' value")
+	}
+	switch name {
+	case ListRegisteredAPIsCommand:
+		svc.mu.Lock()
+		defer svc.mu.Unlock()
+		return map[string]interface{}{"apis": svc.registeredAPIs()}, nil
+	default:
+		return nil, errors.Errorf("no such command: %s", name)
+	}
+}
+
+// registeredAPIs returns each registered API and its resource names, sorted by API.
+func (svc *webService) registeredAPIs() []interface{} {
+	apis := make([]resource.API, 0, len(svc.services))
+	for api := range svc.services {
+		apis = append(apis, api)
+	}
+	sort.Slice(apis, func(i, j int) bool {
+		return apis[i].String() < apis[j].String()
+	})
+
+	registered := make([]interface{}, 0, len(apis))
+	for _, api := range apis {
+		names := svc.services[api].Names()
+		shortNames := make([]string, 0, len(names))
+		for _, n := range names {
+			shortNames = append(shortNames, n.ShortName())
+		}
+		sort.Strings(shortNames)
+		resourceNames := make([]interface{}, 0, len(shortNames))
+		for _, n := range shortNames {
+			resourceNames = append(resourceNames, n)
+		}
+		registered = append(registered, map[string]interface{}{
+			"api":       api.String(),
+			"resources": resourceNames,
+		})
+	}
+	return registered
+}
+
 // StartModule starts the grpc module server.
 func (svc *webService) StartModule(ctx context.Context) error {
 	svc.mu.Lock()
@@ -419,7 +473,8 @@ func (svc *webService) stopWeb() {
 	svc.webWorkers.Wait()
 }
 
-// Close closes a webService via calls to its Cancel func.
+// Close closes a webService via calls to its Cancel func. Streams are drained and the stream
+// server closed before the rpc and http servers are stopped, and the module server is stopped last.
 func (svc *webService) Close(ctx context.Context) error {
 	svc.mu.Lock()
 	defer svc.mu.Unlock()
@@ -552,17 +607,15 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 	utils.PanicCapturingGo(func() {
 		defer svc.webWorkers.Done()
 		<-ctx.Done()
-		defer func() {
-			if err := httpServer.Shutdown(context.Background()); err != nil {
-				svc.logger.Errorw("error shutting down", "error", err)
-			}
-		}()
-		defer func() {
-			if err := svc.rpcServer.Stop(); err != nil {
-				svc.logger.Errorw("error stopping rpc server", "error", err)
-			}
-		}()
+		// Tear down in order: stop and drain the streams first so that none of them write to
+		// a stopped rpc server, then stop the rpc server and finally the http server.
 		svc.closeStreamServer()
+		if err := svc.rpcServer.Stop(); err != nil {
+			svc.logger.Errorw("error stopping rpc server", "error", err)
+		}
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			svc.logger.Errorw("error shutting down", "error", err)
+		}
 	})
 	svc.webWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	streampb "go.viam.com/api/stream/v1"
 	"go.viam.com/utils"
 	"go.viam.com/utils/rpc"
@@ -77,6 +78,22 @@ type webService struct {
 
 	videoSources map[string]gostream.HotSwappableVideoSource
 	audioSources map[string]gostream.HotSwappableAudioSource
+
+	// streamMu guards streamsClosed, startedStreams and additions to streamWorkers so that no
+	// stream can be started once the stream server has begun closing.
+	streamMu       sync.Mutex
+	streamsClosed  bool
+	streamWorkers  sync.WaitGroup
+	startedStreams map[string]startedStream
+}
+
+// startedStream is a stream being fed by a stream worker.
+type startedStream struct {
+	stream gostream.Stream
+	// cancel stops the worker feeding the stream.
+	cancel func()
+	// done is closed once the worker has stopped.
+	done chan struct{}
 }
 
 func (svc *webService) streamInitialized() bool {
@@ -95,6 +112,9 @@ func (svc *webService) addNewStreams(ctx context.Context) error {
 		}
 		return nil
 	}
+	if err := svc.removeStaleStreams(); err != nil {
+		return err
+	}
 
 	newStream := func(name string, isVideo bool) (gostream.Stream, bool, error) {
 		// Configure new stream
@@ -104,6 +124,7 @@ func (svc *webService) addNewStreams(ctx context.Context) error {
 
 		if isVideo {
 			config.VideoEncoderFactory = svc.opts.streamConfig.VideoEncoderFactory
+			config.KeyFrameInterval = svc.opts.streamConfig.KeyFrameInterval
 		} else {
 			config.AudioEncoderFactory = svc.opts.streamConfig.AudioEncoderFactory
 		}
@@ -150,6 +171,32 @@ func (svc *webService) addNewStreams(ctx context.Context) error {
 	return nil
 }
 
+// removeStaleStreams closes the streams whose sources are no longer on the robot. Streams of
+// sources which are still present are left running so that their viewers are not interrupted.
+func (svc *webService) removeStaleStreams() error {
+	svc.streamMu.Lock()
+	var stale []startedStream
+	for name, started := range svc.startedStreams {
+		_, isVideo := svc.videoSources[name]
+		_, isAudio := svc.audioSources[name]
+		if isVideo || isAudio {
+			continue
+		}
+		started.cancel()
+		delete(svc.startedStreams, name)
+		stale = append(stale, started)
+	}
+	svc.streamMu.Unlock()
+
+	var errs error
+	for _, started := range stale {
+		// Wait for the worker to stop feeding the stream before closing it out from under it.
+		<-started.done
+		errs = multierr.Combine(errs, svc.streamServer.Server.CloseStream(started.stream.Name()))
+	}
+	return errs
+}
+
 func (svc *webService) makeStreamServer(ctx context.Context) (*StreamServer, error) {
 	svc.refreshVideoSources()
 	svc.refreshAudioSources()
@@ -231,11 +278,27 @@ func (svc *webService) makeStreamServer(ctx context.Context) (*StreamServer, err
 	return &StreamServer{streamServer, true}, nil
 }
 
-func (svc *webService) startStream(streamFunc func(opts *webstream.BackoffTuningOptions) error) {
+func (svc *webService) startStream(
+	stream gostream.Stream,
+	cancel func(),
+	streamFunc func(opts *webstream.BackoffTuningOptions) error,
+) {
+	svc.streamMu.Lock()
+	if svc.streamsClosed {
+		svc.streamMu.Unlock()
+		cancel()
+		svc.logger.Debug("not starting stream since the stream server is closing")
+		return
+	}
+	done := make(chan struct{})
+	svc.startedStreams[stream.Name()] = startedStream{stream: stream, cancel: cancel, done: done}
+	svc.streamWorkers.Add(1)
+	svc.streamMu.Unlock()
+
 	waitCh := make(chan struct{})
-	svc.webWorkers.Add(1)
 	utils.PanicCapturingGo(func() {
-		defer svc.webWorkers.Done()
+		defer svc.streamWorkers.Done()
+		defer close(done)
 		close(waitCh)
 		opts := &webstream.BackoffTuningOptions{
 			BaseSleep: 50 * time.Microsecond,
@@ -266,8 +329,8 @@ func (svc *webService) propertiesFromStream(ctx context.Context, stream gostream
 }
 
 func (svc *webService) startVideoStream(ctx context.Context, source gostream.VideoSource, stream gostream.Stream) {
-	svc.startStream(func(opts *webstream.BackoffTuningOptions) error {
-		streamVideoCtx, _ := utils.MergeContext(svc.cancelCtx, ctx)
+	streamVideoCtx, cancel := utils.MergeContext(svc.cancelCtx, ctx)
+	svc.startStream(stream, cancel, func(opts *webstream.BackoffTuningOptions) error {
 		// Use H264 for cameras that support it; but do not override upstream values.
 		if props, err := svc.propertiesFromStream(ctx, stream); err == nil && slices.Contains(props.MimeTypes, rutils.MimeTypeH264) {
 			streamVideoCtx = gostream.WithMIMETypeHint(streamVideoCtx, rutils.WithLazyMIMEType(rutils.MimeTypeH264))
@@ -278,20 +341,26 @@ func (svc *webService) startVideoStream(ctx context.Context, source gostream.Vid
 }
 
 func (svc *webService) startAudioStream(ctx context.Context, source gostream.AudioSource, stream gostream.Stream) {
-	svc.startStream(func(opts *webstream.BackoffTuningOptions) error {
-		// Merge ctx that may be coming from a Reconfigure.
-		streamAudioCtx, _ := utils.MergeContext(svc.cancelCtx, ctx)
+	// Merge ctx that may be coming from a Reconfigure.
+	streamAudioCtx, cancel := utils.MergeContext(svc.cancelCtx, ctx)
+	svc.startStream(stream, cancel, func(opts *webstream.BackoffTuningOptions) error {
 		return webstream.StreamAudioSource(streamAudioCtx, source, stream, opts, svc.logger)
 	})
 }
 
-// refreshVideoSources checks and initializes every possible video source that could be viewed from the robot.
+// refreshVideoSources checks and initializes every possible video source that could be viewed from the robot
+// and forgets the ones which are no longer on it.
 func (svc *webService) refreshVideoSources() {
+	present := map[string]struct{}{}
 	for _, name := range camera.NamesFromRobot(svc.r) {
 		cam, err := camera.FromRobot(svc.r, name)
 		if err != nil {
 			continue
 		}
+		if !svc.opts.streamEnabled(cam.Name()) {
+			continue
+		}
+		present[cam.Name().SDPTrackName()] = struct{}{}
 		existing, ok := svc.videoSources[cam.Name().SDPTrackName()]
 		if ok {
 			existing.Swap(cam)
@@ -300,15 +369,26 @@ func (svc *webService) refreshVideoSources() {
 		newSwapper := gostream.NewHotSwappableVideoSource(cam)
 		svc.videoSources[cam.Name().SDPTrackName()] = newSwapper
 	}
+	for name := range svc.videoSources {
+		if _, ok := present[name]; !ok {
+			delete(svc.videoSources, name)
+		}
+	}
 }
 
-// refreshAudioSources checks and initializes every possible audio source that could be viewed from the robot.
+// refreshAudioSources checks and initializes every possible audio source that could be viewed from the robot
+// and forgets the ones which are no longer on it.
 func (svc *webService) refreshAudioSources() {
+	present := map[string]struct{}{}
 	for _, name := range audioinput.NamesFromRobot(svc.r) {
 		input, err := audioinput.FromRobot(svc.r, name)
 		if err != nil {
 			continue
 		}
+		if !svc.opts.streamEnabled(input.Name()) {
+			continue
+		}
+		present[input.Name().SDPTrackName()] = struct{}{}
 		existing, ok := svc.audioSources[input.Name().SDPTrackName()]
 		if ok {
 			existing.Swap(input)
@@ -317,6 +397,11 @@ func (svc *webService) refreshAudioSources() {
 		newSwapper := gostream.NewHotSwappableAudioSource(input)
 		svc.audioSources[input.Name().SDPTrackName()] = newSwapper
 	}
+	for name := range svc.audioSources {
+		if _, ok := present[name]; !ok {
+			delete(svc.audioSources, name)
+		}
+	}
 }
 
 // Update updates the web service when the robot has changed.
@@ -332,7 +417,15 @@ func (svc *webService) Reconfigure(ctx context.Context, deps resource.Dependenci
 	return svc.addNewStreams(svc.cancelCtx)
 }
 
+// closeStreamServer stops any new streams from being started, waits for the active ones to
+// finish and then closes the stream server. It must be called after the web service's context
+// is cancelled and before the rpc server is stopped so that no stream writes to a stopped server.
 func (svc *webService) closeStreamServer() {
+	svc.streamMu.Lock()
+	svc.streamsClosed = true
+	svc.streamMu.Unlock()
+	svc.streamWorkers.Wait()
+
 	if svc.streamServer.Server != nil {
 		if err := svc.streamServer.Server.Close(); err != nil {
 			svc.logger.Errorw("error closing stream server", "error", err)
@@ -341,6 +434,11 @@ func (svc *webService) closeStreamServer() {
 }
 
 func (svc *webService) initStreamServer(ctx context.Context, options *weboptions.Options) error {
+	svc.streamMu.Lock()
+	svc.streamsClosed = false
+	svc.startedStreams = map[string]startedStream{}
+	svc.streamMu.Unlock()
+
 	var err error
 	svc.streamServer, err = svc.makeStreamServer(ctx)
 	if err != nil {
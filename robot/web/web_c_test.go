@@ -0,0 +1,82 @@
+package web
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/gostream/codec/x264"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/testutils/robottestutils"
+)
+
+func TestWebReconfigureOnlyChangesStreamDelta(t *testing.T) {
+	newCamera := func(name string) *inject.Camera {
+		cam := inject.NewCamera(name)
+		cam.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+			return camera.Properties{}, nil
+		}
+		return cam
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := logging.NewTestLogger(t)
+	robot := &inject.Robot{}
+	cam1 := newCamera("camera1")
+	cam2 := newCamera("camera2")
+	rs := map[resource.Name]resource.Resource{cam1.Name(): cam1, cam2.Name(): cam2}
+	robot.MockResourcesFromMap(rs)
+	robot.LoggerFunc = func() logging.Logger { return logger }
+
+	options, _, _ := robottestutils.CreateBaseOptionsAndListener(t)
+	svc, ok := New(robot, logger, WithStreamConfig(x264.DefaultStreamConfig)).(*webService)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	started := func() map[string]startedStream {
+		svc.streamMu.Lock()
+		defer svc.streamMu.Unlock()
+		streams := make(map[string]startedStream, len(svc.startedStreams))
+		for name, s := range svc.startedStreams {
+			streams[name] = s
+		}
+		return streams
+	}
+	before := started()
+	test.That(t, before, test.ShouldHaveLength, 2)
+
+	// add a camera and remove another
+	cam3 := newCamera("camera3")
+	robot.Mu.Lock()
+	rs[cam3.Name()] = cam3
+	delete(rs, cam2.Name())
+	robot.Mu.Unlock()
+	robot.MockResourcesFromMap(rs)
+	test.That(t, svc.Reconfigure(ctx, rs, resource.Config{}), test.ShouldBeNil)
+
+	after := started()
+	test.That(t, after, test.ShouldHaveLength, 2)
+	test.That(t, after, test.ShouldContainKey, "camera3")
+	test.That(t, after, test.ShouldNotContainKey, "camera2")
+	// the removed camera's stream worker stopped before its stream was closed
+	select {
+	case <-before["camera2"].done:
+	default:
+		t.Fatal("expected the removed stream's worker to have stopped")
+	}
+	// the stream of the unchanged camera is the same one, not a rebuilt copy
+	test.That(t, after["camera1"].stream, test.ShouldEqual, before["camera1"].stream)
+
+	resp, err := svc.streamServer.Server.ListStreams(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Names, test.ShouldHaveLength, 2)
+	test.That(t, resp.Names, test.ShouldContain, "camera1")
+	test.That(t, resp.Names, test.ShouldContain, "camera3")
+
+	cancel()
+	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+}
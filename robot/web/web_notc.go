@@ -69,3 +69,8 @@ func (svc *webService) initStreamServer(ctx context.Context, options *weboptions
 
 // stub for missing gostream
 type options struct{}
+
+// stub implementation when gostream not available
+func (o *options) validate() error {
+	return nil
+}
@@ -2,12 +2,23 @@
 
 package web
 
-import "go.viam.com/rdk/gostream"
+import (
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/resource"
+)
 
 // options configures a web service.
 type options struct {
 	// streamConfig is used to enable audio/video streaming over WebRTC.
 	streamConfig *gostream.StreamConfig
+
+	// streamAllowList, if not empty, restricts streams to the resources with these names.
+	streamAllowList map[string]struct{}
+
+	// streamDenyList prevents the resources with these names from being streamed.
+	streamDenyList map[string]struct{}
 }
 
 // WithStreamConfig returns an Option which sets the streamConfig
@@ -17,3 +28,59 @@ func WithStreamConfig(config gostream.StreamConfig) Option {
 		o.streamConfig = &config
 	})
 }
+
+// WithStreamAllowList returns an Option which only streams the resources with the given
+// names. Names of remote resources must include the remote, e.g. "remote1:camera1".
+// By default every stream-capable resource is streamed.
+func WithStreamAllowList(names ...string) Option {
+	return newFuncOption(func(o *options) {
+		o.streamAllowList = addStreamNames(o.streamAllowList, names)
+	})
+}
+
+// WithStreamDenyList returns an Option which does not stream the resources with the given
+// names. Names of remote resources must include the remote, e.g. "remote1:camera1".
+func WithStreamDenyList(names ...string) Option {
+	return newFuncOption(func(o *options) {
+		o.streamDenyList = addStreamNames(o.streamDenyList, names)
+	})
+}
+
+func addStreamNames(set map[string]struct{}, names []string) map[string]struct{} {
+	if set == nil {
+		set = make(map[string]struct{}, len(names))
+	}
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return set
+}
+
+// streamEnabled returns whether the resource with the given name should be streamed.
+func (o *options) streamEnabled(name resource.Name) bool {
+	if _, ok := o.streamDenyList[name.ShortName()]; ok {
+		return false
+	}
+	if len(o.streamAllowList) == 0 {
+		return true
+	}
+	_, ok := o.streamAllowList[name.ShortName()]
+	return ok
+}
+
+// validate ensures the applied options can be used to start the web service. A stream config
+// that is set but has no encoder factories would otherwise only fail once streams are created.
+func (o *options) validate() error {
+	for name := range o.streamAllowList {
+		if _, ok := o.streamDenyList[name]; ok {
+			return errors.Errorf("resource %q is both allowed and denied streaming", name)
+		}
+	}
+	if o.streamConfig == nil {
+		return nil
+	}
+	if o.streamConfig.VideoEncoderFactory == nil && o.streamConfig.AudioEncoderFactory == nil {
+		return errors.New("stream config must specify at least one of a video or audio encoder factory")
+	}
+	return nil
+}
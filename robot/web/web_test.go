@@ -1009,6 +1009,214 @@ func TestWebStreamImmediateClose(t *testing.T) {
 	<-ctx.Done()
 }
 
+func TestWebCloseWhileStreaming(t *testing.T) {
+	robot := &inject.Robot{}
+	cam1 := inject.NewCamera("camera1")
+	cam1.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{}, nil
+	}
+	rs := map[resource.Name]resource.Resource{
+		cam1.Name():               cam1,
+		audioinput.Named("audio"): &inject.AudioInput{},
+	}
+	robot.MockResourcesFromMap(rs)
+
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	robot.LoggerFunc = func() logging.Logger { return logger }
+	svc := web.New(robot, logger, web.WithStreamConfig(gostream.StreamConfig{
+		AudioEncoderFactory: opus.NewEncoderFactory(),
+		VideoEncoderFactory: x264.NewEncoderFactory(),
+	}))
+
+	// the service can be restarted after its streams have been drained and closed
+	for i := 0; i < 2; i++ {
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		conn, err := rgrpc.Dial(ctx, addr, logger)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := streampb.NewStreamServiceClient(conn).ListStreams(ctx, &streampb.ListStreamsRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.Names, test.ShouldHaveLength, 2)
+
+		// closing without cancelling the start context first must stop the streams before the
+		// rpc server; leaked stream workers are caught by the package's TestMain.
+		test.That(t, svc.Close(ctx), test.ShouldBeNil)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}
+}
+
+func TestWebListRegisteredAPIs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := logging.NewTestLogger(t)
+
+	robot := &inject.Robot{}
+	cam1 := inject.NewCamera("camera1")
+	rs := map[resource.Name]resource.Resource{cam1.Name(): cam1}
+	robot.MockResourcesFromMap(rs)
+	robot.LoggerFunc = func() logging.Logger { return logger }
+
+	options, _, _ := robottestutils.CreateBaseOptionsAndListener(t)
+	svc := web.New(robot, logger)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	cameraResources := func() []interface{} {
+		resp, err := svc.DoCommand(ctx, map[string]interface{}{"command": web.ListRegisteredAPIsCommand})
+		test.That(t, err, test.ShouldBeNil)
+		apis, ok := resp["apis"].([]interface{})
+		test.That(t, ok, test.ShouldBeTrue)
+		for _, a := range apis {
+			entry, ok := a.(map[string]interface{})
+			test.That(t, ok, test.ShouldBeTrue)
+			if entry["api"] == camera.API.String() {
+				resources, ok := entry["resources"].([]interface{})
+				test.That(t, ok, test.ShouldBeTrue)
+				return resources
+			}
+		}
+		t.Fatalf("%s missing from registered apis", camera.API)
+		return nil
+	}
+	test.That(t, cameraResources(), test.ShouldResemble, []interface{}{"camera1"})
+
+	cam2 := inject.NewCamera("camera2")
+	robot.Mu.Lock()
+	rs[cam2.Name()] = cam2
+	robot.Mu.Unlock()
+	robot.MockResourcesFromMap(rs)
+	test.That(t, svc.Reconfigure(ctx, rs, resource.Config{}), test.ShouldBeNil)
+	test.That(t, cameraResources(), test.ShouldResemble, []interface{}{"camera1", "camera2"})
+
+	_, err := svc.DoCommand(ctx, map[string]interface{}{"command": "unknown"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
+
+func TestWebStreamAllowDenyList(t *testing.T) {
+	newRobot := func(logger logging.Logger, names ...string) (*inject.Robot, map[resource.Name]resource.Resource) {
+		robot := &inject.Robot{}
+		rs := map[resource.Name]resource.Resource{}
+		for _, name := range names {
+			cam := inject.NewCamera(name)
+			cam.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+				return camera.Properties{}, nil
+			}
+			rs[cam.Name()] = cam
+		}
+		robot.MockResourcesFromMap(rs)
+		robot.LoggerFunc = func() logging.Logger { return logger }
+		return robot, rs
+	}
+	listStreams := func(ctx context.Context, t *testing.T, addr string, logger logging.Logger) []string {
+		conn, err := rgrpc.Dial(ctx, addr, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		resp, err := streampb.NewStreamServiceClient(conn).ListStreams(ctx, &streampb.ListStreamsRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		return resp.Names
+	}
+
+	t.Run("allow list", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		logger := logging.NewTestLogger(t)
+		robot, rs := newRobot(logger, "camera1", "camera2", "camera3")
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		svc := web.New(robot, logger,
+			web.WithStreamConfig(x264.DefaultStreamConfig),
+			web.WithStreamAllowList("camera1", "camera2"),
+		)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		names := listStreams(ctx, t, addr, logger)
+		test.That(t, names, test.ShouldHaveLength, 2)
+		test.That(t, names, test.ShouldContain, "camera1")
+		test.That(t, names, test.ShouldContain, "camera2")
+
+		// cameras added later are only streamed if they are allowed
+		cam4 := inject.NewCamera("camera4")
+		robot.Mu.Lock()
+		rs[cam4.Name()] = cam4
+		robot.Mu.Unlock()
+		robot.MockResourcesFromMap(rs)
+		test.That(t, svc.Reconfigure(ctx, rs, resource.Config{}), test.ShouldBeNil)
+		test.That(t, listStreams(ctx, t, addr, logger), test.ShouldHaveLength, 2)
+
+		cancel()
+		test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+	})
+
+	t.Run("deny list", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		logger := logging.NewTestLogger(t)
+		robot, _ := newRobot(logger, "camera1", "camera2", "camera3")
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		svc := web.New(robot, logger,
+			web.WithStreamConfig(x264.DefaultStreamConfig),
+			web.WithStreamDenyList("camera3"),
+		)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		names := listStreams(ctx, t, addr, logger)
+		test.That(t, names, test.ShouldHaveLength, 2)
+		test.That(t, names, test.ShouldNotContain, "camera3")
+
+		cancel()
+		test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+	})
+
+	t.Run("a resource can not be both allowed and denied", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		robot, _ := newRobot(logger, "camera1")
+		options, _, _ := robottestutils.CreateBaseOptionsAndListener(t)
+		svc := web.New(robot, logger,
+			web.WithStreamConfig(x264.DefaultStreamConfig),
+			web.WithStreamAllowList("camera1"),
+			web.WithStreamDenyList("camera1"),
+		)
+		err := svc.Start(context.Background(), options)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "both allowed and denied")
+		test.That(t, options.Network.Listener.Close(), test.ShouldBeNil)
+	})
+}
+
+func TestWebStreamConfigValidation(t *testing.T) {
+	robot := &inject.Robot{}
+	cam1 := inject.NewCamera("camera1")
+	cam1.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{}, nil
+	}
+	robot.MockResourcesFromMap(map[resource.Name]resource.Resource{cam1.Name(): cam1})
+
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	robot.LoggerFunc = func() logging.Logger { return logger }
+
+	// a stream config without any encoder factories is rejected before the server starts
+	options, _, _ := robottestutils.CreateBaseOptionsAndListener(t)
+	svc := web.New(robot, logger, web.WithStreamConfig(gostream.StreamConfig{}))
+	err := svc.Start(ctx, options)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must specify at least one of a video or audio encoder factory")
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+	test.That(t, options.Network.Listener.Close(), test.ShouldBeNil)
+
+	// a single encoder factory is enough
+	options, _, _ = robottestutils.CreateBaseOptionsAndListener(t)
+	svc = web.New(robot, logger, web.WithStreamConfig(gostream.StreamConfig{
+		VideoEncoderFactory: x264.NewEncoderFactory(),
+	}))
+	cancelCtx, cancel := context.WithCancel(ctx)
+	test.That(t, svc.Start(cancelCtx, options), test.ShouldBeNil)
+	cancel()
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
+
 func setupRobotCtx(t *testing.T) (context.Context, robot.Robot) {
 	t.Helper()
 
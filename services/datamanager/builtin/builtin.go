@@ -67,6 +67,9 @@ var (
 var errCaptureDirectoryConfigurationDisabled = errors.New("changing the capture directory is prohibited in this environment")
 
 // Config describes how to configure the service.
+// IgnoredFileGlobs are filepath.Match patterns of the base names of arbitrary files which are never
+// synced. They take precedence over FileLastModifiedMillis, so a matching file is skipped however
+// long ago it was modified. They don't apply to data capture files.
 type Config struct {
 	CaptureDir                 string   `json:"capture_dir"`
 	AdditionalSyncPaths        []string `json:"additional_sync_paths"`
@@ -78,10 +81,25 @@ type Config struct {
 	SelectiveSyncerName        string   `json:"selective_syncer_name"`
 	MaximumNumSyncThreads      int      `json:"maximum_num_sync_threads"`
 	DeleteEveryNthWhenDiskFull int      `json:"delete_every_nth_when_disk_full"`
+	UploadChunkSizeBytes       int      `json:"upload_chunk_size_bytes"`
+	CompressArbitraryFiles     bool     `json:"compress_arbitrary_files"`
+	MaxUploadBytesPerSec       int      `json:"max_upload_bytes_per_sec"`
+	IgnoredFileGlobs           []string `json:"ignored_file_globs"`
 }
 
 // Validate returns components which will be depended upon weakly due to the above matcher.
 func (c *Config) Validate(path string) ([]string, error) {
+	if c.UploadChunkSizeBytes < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("upload_chunk_size_bytes can't be negative"))
+	}
+	if c.MaxUploadBytesPerSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("max_upload_bytes_per_sec can't be negative"))
+	}
+	for _, glob := range c.IgnoredFileGlobs {
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return nil, resource.NewConfigValidationError(path, errors.Wrapf(err, "invalid ignored_file_globs pattern %q", glob))
+		}
+	}
 	return []string{cloud.InternalServiceName.String()}, nil
 }
 
@@ -124,13 +142,18 @@ type builtIn struct {
 	fileLastModifiedMillis int
 
 	additionalSyncPaths []string
+	ignoredFileGlobs    []string
 	tags                []string
 	syncDisabled        bool
 	syncIntervalMins    float64
 	syncRoutineCancelFn context.CancelFunc
 	syncer              datasync.Manager
+	currentSyncer       currentSyncer
 	syncerConstructor   datasync.ManagerConstructor
 	maxSyncThreads      int
+	uploadChunkSize     int
+	compressFiles       bool
+	maxUploadRate       int
 	cloudConnSvc        cloud.ConnectionService
 	cloudConn           rpc.ClientConn
 	syncTicker          *clk.Ticker
@@ -139,6 +162,8 @@ type builtIn struct {
 	selectiveSyncEnabled bool
 
 	componentMethodFrequencyHz map[resourceMethodMetadata]float32
+	// recoveredDirs are the directories whose left behind in progress files have been recovered.
+	recoveredDirs map[string]struct{}
 
 	fileDeletionRoutineCancelFn   context.CancelFunc
 	fileDeletionBackgroundWorkers *sync.WaitGroup
@@ -163,8 +188,10 @@ func NewBuiltIn(
 		tags:                       []string{},
 		fileLastModifiedMillis:     defaultFileLastModifiedMillis,
 		syncerConstructor:          datasync.NewManager,
+		uploadChunkSize:            datasync.DefaultUploadChunkSize,
 		selectiveSyncEnabled:       false,
 		componentMethodFrequencyHz: make(map[resourceMethodMetadata]float32),
+		recoveredDirs:              make(map[string]struct{}),
 	}
 
 	if err := svc.Reconfigure(ctx, deps, conf); err != nil {
@@ -326,11 +353,21 @@ func (svc *builtIn) initializeOrUpdateCollector(
 	if err := os.MkdirAll(targetDir, 0o700); err != nil {
 		return nil, err
 	}
+	var bufferOpts []datacapture.BufferOption
+	if config.CompressFiles {
+		bufferOpts = append(bufferOpts, datacapture.WithCompression())
+	}
+	if config.MaxTotalBytes > 0 {
+		bufferOpts = append(bufferOpts, datacapture.WithMaxTotalBytes(config.MaxTotalBytes, &svc.currentSyncer, svc.logger))
+	}
+	if config.BatchBinaryData {
+		bufferOpts = append(bufferOpts, datacapture.WithBinaryBatching())
+	}
 	params := data.CollectorParams{
 		ComponentName: config.Name.ShortName(),
 		Interval:      interval,
 		MethodParams:  methodParams,
-		Target:        datacapture.NewBuffer(targetDir, captureMetadata),
+		Target:        datacapture.NewBuffer(targetDir, captureMetadata, bufferOpts...),
 		QueueSize:     captureQueueSize,
 		BufferSize:    captureBufferSize,
 		Logger:        svc.logger,
@@ -350,12 +387,44 @@ func (svc *builtIn) closeSyncer() {
 		// If previously we were syncing, close the old syncer and cancel the old updateCollectors goroutine.
 		svc.syncer.Close()
 		svc.syncer = nil
+		svc.currentSyncer.set(nil)
 	}
 	if svc.cloudConn != nil {
 		goutils.UncheckedError(svc.cloudConn.Close())
 	}
 }
 
+// currentSyncer marks files in progress with the data manager's current syncer, if it has one, so capture
+// buffers don't evict files while they are being synced. Buffers outlive the syncer, which is replaced
+// when sync is reconfigured.
+type currentSyncer struct {
+	mu     sync.Mutex
+	syncer datasync.Manager
+}
+
+func (c *currentSyncer) set(syncer datasync.Manager) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncer = syncer
+}
+
+func (c *currentSyncer) MarkInProgress(path string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.syncer == nil {
+		return true
+	}
+	return c.syncer.MarkInProgress(path)
+}
+
+func (c *currentSyncer) UnmarkInProgress(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.syncer != nil {
+		c.syncer.UnmarkInProgress(path)
+	}
+}
+
 var grpcConnectionTimeout = 10 * time.Second
 
 func (svc *builtIn) initSyncer(ctx context.Context) error {
@@ -365,17 +434,20 @@ func (svc *builtIn) initSyncer(ctx context.Context) error {
 	if errors.Is(err, cloud.ErrNotCloudManaged) {
 		svc.logger.CDebug(ctx, "Using no-op sync manager when not cloud managed")
 		svc.syncer = datasync.NewNoopManager()
+		svc.currentSyncer.set(svc.syncer)
 	}
 	if err != nil {
 		return err
 	}
 
 	client := v1.NewDataSyncServiceClient(conn)
-	syncer, err := svc.syncerConstructor(identity, client, svc.logger, svc.captureDir, svc.maxSyncThreads)
+	syncer, err := svc.syncerConstructor(identity, client, svc.logger, svc.captureDir, svc.maxSyncThreads, svc.uploadChunkSize,
+		svc.compressFiles, svc.maxUploadRate)
 	if err != nil {
 		return errors.Wrap(err, "failed to initialize new syncer")
 	}
 	svc.syncer = syncer
+	svc.currentSyncer.set(syncer)
 	svc.cloudConn = conn
 	return nil
 }
@@ -418,8 +490,15 @@ func (svc *builtIn) Reconfigure(
 	if err != nil {
 		return err
 	}
-	// Syncer should be reinitialized if the max sync threads are updated in the config
-	reinitSyncer := cloudConnSvc != svc.cloudConnSvc || svcConfig.MaximumNumSyncThreads != svc.maxSyncThreads
+	uploadChunkSize := svcConfig.UploadChunkSizeBytes
+	if uploadChunkSize == 0 {
+		uploadChunkSize = datasync.DefaultUploadChunkSize
+	}
+	// Syncer should be reinitialized if the max sync threads, upload chunk size, compression or upload limit are
+	// updated in the config
+	reinitSyncer := cloudConnSvc != svc.cloudConnSvc || svcConfig.MaximumNumSyncThreads != svc.maxSyncThreads ||
+		uploadChunkSize != svc.uploadChunkSize || svcConfig.CompressArbitraryFiles != svc.compressFiles ||
+		svcConfig.MaxUploadBytesPerSec != svc.maxUploadRate
 	svc.cloudConnSvc = cloudConnSvc
 
 	captureConfigs, err := svc.updateDataCaptureConfigs(deps, conf, svcConfig.CaptureDir)
@@ -437,6 +516,15 @@ func (svc *builtIn) Reconfigure(
 		svc.captureDir = viamCaptureDotDir
 	}
 	svc.captureDisabled = svcConfig.CaptureDisabled
+	// In progress files left behind, e.g. by a crash, are recovered the first time a directory is
+	// configured, before any collector opens a buffer in it. Afterwards any in progress file in the
+	// directory belongs to a live buffer, however long ago it was written to, so it is left alone.
+	for _, dir := range append([]string{svc.captureDir}, svcConfig.AdditionalSyncPaths...) {
+		if _, recovered := svc.recoveredDirs[dir]; !recovered {
+			recoverInProgressFiles(dir, svc.logger)
+			svc.recoveredDirs[dir] = struct{}{}
+		}
+	}
 	// Service is disabled, so close all collectors and clear the map so we can instantiate new ones if we enable this service.
 	if svc.captureDisabled {
 		svc.closeCollectors()
@@ -514,6 +602,7 @@ func (svc *builtIn) Reconfigure(
 	}
 	svc.collectors = newCollectors
 	svc.additionalSyncPaths = svcConfig.AdditionalSyncPaths
+	svc.ignoredFileGlobs = svcConfig.IgnoredFileGlobs
 
 	fileLastModifiedMillis := svcConfig.FileLastModifiedMillis
 	if fileLastModifiedMillis <= 0 {
@@ -537,7 +626,8 @@ func (svc *builtIn) Reconfigure(
 
 	syncConfigUpdated := svc.syncDisabled != svcConfig.ScheduledSyncDisabled || svc.syncIntervalMins != svcConfig.SyncIntervalMins ||
 		!reflect.DeepEqual(svc.tags, svcConfig.Tags) || svc.fileLastModifiedMillis != fileLastModifiedMillis ||
-		svc.maxSyncThreads != svcConfig.MaximumNumSyncThreads
+		svc.maxSyncThreads != svcConfig.MaximumNumSyncThreads || svc.uploadChunkSize != uploadChunkSize ||
+		svc.compressFiles != svcConfig.CompressArbitraryFiles || svc.maxUploadRate != svcConfig.MaxUploadBytesPerSec
 
 	if syncConfigUpdated {
 		svc.syncDisabled = svcConfig.ScheduledSyncDisabled
@@ -549,6 +639,9 @@ func (svc *builtIn) Reconfigure(
 			maxThreads = svcConfig.MaximumNumSyncThreads
 		}
 		svc.maxSyncThreads = maxThreads
+		svc.uploadChunkSize = uploadChunkSize
+		svc.compressFiles = svcConfig.CompressArbitraryFiles
+		svc.maxUploadRate = svcConfig.MaxUploadBytesPerSec
 
 		svc.cancelSyncScheduler()
 		if !svc.syncDisabled && svc.syncIntervalMins != 0.0 {
@@ -652,9 +745,9 @@ func (svc *builtIn) sync() {
 	svc.flushCollectors()
 
 	svc.lock.Lock()
-	toSync := getAllFilesToSync(svc.captureDir, svc.fileLastModifiedMillis)
+	toSync := getAllFilesToSync(svc.captureDir, svc.fileLastModifiedMillis, svc.ignoredFileGlobs)
 	for _, ap := range svc.additionalSyncPaths {
-		toSync = append(toSync, getAllFilesToSync(ap, svc.fileLastModifiedMillis)...)
+		toSync = append(toSync, getAllFilesToSync(ap, svc.fileLastModifiedMillis, svc.ignoredFileGlobs)...)
 	}
 	svc.lock.Unlock()
 
@@ -664,7 +757,7 @@ func (svc *builtIn) sync() {
 }
 
 //nolint
-func getAllFilesToSync(dir string, lastModifiedMillis int) []string {
+func getAllFilesToSync(dir string, lastModifiedMillis int, ignoredGlobs []string) []string {
 	var filePaths []string
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -685,12 +778,18 @@ func getAllFilesToSync(dir string, lastModifiedMillis int) []string {
 		if timeSinceMod < 0 {
 			timeSinceMod = 0
 		}
-		isStuckInProgressCaptureFile := filepath.Ext(path) == datacapture.InProgressFileExt &&
-			timeSinceMod >= defaultFileLastModifiedMillis*time.Millisecond
-		isNonCaptureFileThatIsNotBeingWrittenTo := filepath.Ext(path) != datacapture.InProgressFileExt &&
-			timeSinceMod >= time.Duration(lastModifiedMillis)*time.Millisecond
-		isCompletedCaptureFile := filepath.Ext(path) == datacapture.FileExt
-		if isCompletedCaptureFile || isStuckInProgressCaptureFile || isNonCaptureFileThatIsNotBeingWrittenTo {
+		// In progress capture files are never synced; they are either being written to by a buffer
+		// or were left behind & recovered by recoverInProgressFiles.
+		if filepath.Ext(path) == datacapture.InProgressFileExt {
+			return nil
+		}
+		isCompletedCaptureFile := filepath.Ext(path) == datacapture.FileExt || datacapture.IsCompressedDataCaptureFile(path)
+		// Ignored arbitrary files are never synced, regardless of when they were last modified.
+		if !isCompletedCaptureFile && isIgnoredFile(path, ignoredGlobs) {
+			return nil
+		}
+		isNonCaptureFileThatIsNotBeingWrittenTo := timeSinceMod >= time.Duration(lastModifiedMillis)*time.Millisecond
+		if isCompletedCaptureFile || isNonCaptureFileThatIsNotBeingWrittenTo {
 			filePaths = append(filePaths, path)
 		}
 		return nil
@@ -698,6 +797,41 @@ func getAllFilesToSync(dir string, lastModifiedMillis int) []string {
 	return filePaths
 }
 
+// recoverInProgressFiles recovers the in progress capture files in dir left behind if the process
+// exited before completing them, so that they are synced. The rename is atomic, so a file is either
+// skipped or synced in its entirety. Left behind compression & decompression files whose data is held
+// by another file are removed rather than synced twice. It must only be called while no buffer is
+// writing to dir.
+func recoverInProgressFiles(dir string, logger logging.Logger) {
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() && info.Name() == datasync.FailedDir {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || filepath.Ext(path) != datacapture.InProgressFileExt {
+			return nil
+		}
+		if _, err := datacapture.RecoverInProgressFile(path); err != nil {
+			logger.Warnw("failed to recover in progress capture file", "path", path, "error", err)
+		}
+		return nil
+	})
+}
+
+// isIgnoredFile returns whether the base name of the file at path matches any of the ignored globs.
+func isIgnoredFile(path string, ignoredGlobs []string) bool {
+	name := filepath.Base(path)
+	for _, glob := range ignoredGlobs {
+		// the globs are validated with the config, so a match can't error
+		if matched, err := filepath.Match(glob, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 // Build the component configs associated with the data manager service.
 func (svc *builtIn) updateDataCaptureConfigs(
 	resources resource.Dependencies,
@@ -142,7 +142,8 @@ func TestFileDeletion(t *testing.T) {
 
 			var syncer datasync.Manager
 			if tc.syncEnabled {
-				s, err := datasync.NewManager("rick astley", mockClient, logger, tempCaptureDir, datasync.MaxParallelSyncRoutines)
+				s, err := datasync.NewManager("rick astley", mockClient, logger, tempCaptureDir,
+					datasync.MaxParallelSyncRoutines, datasync.DefaultUploadChunkSize, false, 0)
 				test.That(t, err, test.ShouldBeNil)
 				syncer = s
 				defer syncer.Close()
@@ -1,11 +1,16 @@
 package builtin
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -15,6 +20,8 @@ import (
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -330,8 +337,6 @@ func TestDataCaptureUploadIntegration(t *testing.T) {
 func TestArbitraryFileUpload(t *testing.T) {
 	// Set exponential factor to 1 and retry wait time to 20ms so retries happen very quickly.
 	datasync.RetryExponentialFactor.Store(int32(1))
-	fileName := "some_file_name.txt"
-	fileExt := ".txt"
 	// Disable the check to see if the file was modified recently,
 	// since we are testing instanteous arbitrary file uploads.
 	datasync.SetFileLastModifiedMillis(0)
@@ -340,12 +345,48 @@ func TestArbitraryFileUpload(t *testing.T) {
 		manualSync           bool
 		scheduleSyncDisabled bool
 		serviceFail          bool
+		uploadChunkSizeBytes int
+		compress             bool
+		fileName             string
+		expectedFileExt      string
+		expectCompressed     bool
+		maxUploadBytesPerSec int
 	}{
 		{
 			name:                 "scheduled sync of arbitrary files should work",
 			manualSync:           false,
 			scheduleSyncDisabled: false,
 		},
+		{
+			name:                 "arbitrary files should be uploaded in chunks of the configured size",
+			manualSync:           false,
+			scheduleSyncDisabled: false,
+			uploadChunkSizeBytes: 1000,
+		},
+		{
+			name:                 "arbitrary file uploads should not exceed the configured throughput",
+			manualSync:           false,
+			scheduleSyncDisabled: false,
+			uploadChunkSizeBytes: 1000,
+			maxUploadBytesPerSec: 20000,
+		},
+		{
+			name:                 "arbitrary files should be gzip compressed if compression is enabled",
+			manualSync:           false,
+			scheduleSyncDisabled: false,
+			uploadChunkSizeBytes: 100,
+			compress:             true,
+			expectedFileExt:      ".txt.gz",
+			expectCompressed:     true,
+		},
+		{
+			name:                 "already compressed arbitrary files should not be compressed again",
+			manualSync:           false,
+			scheduleSyncDisabled: false,
+			compress:             true,
+			fileName:             "some_file_name.png",
+			expectedFileExt:      ".png",
+		},
 		{
 			name:                 "manual sync of arbitrary files should work",
 			manualSync:           true,
@@ -366,6 +407,14 @@ func TestArbitraryFileUpload(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
+			fileName := "some_file_name.txt"
+			if tc.fileName != "" {
+				fileName = tc.fileName
+			}
+			fileExt := ".txt"
+			if tc.expectedFileExt != "" {
+				fileExt = tc.expectedFileExt
+			}
 			// Set up server.
 			mockClock := clk.NewMock()
 			clock = mockClock
@@ -389,6 +438,9 @@ func TestArbitraryFileUpload(t *testing.T) {
 			cfg.SyncIntervalMins = syncIntervalMins
 			cfg.AdditionalSyncPaths = []string{additionalPathsDir}
 			cfg.CaptureDir = captureDir
+			cfg.UploadChunkSizeBytes = tc.uploadChunkSizeBytes
+			cfg.CompressArbitraryFiles = tc.compress
+			cfg.MaxUploadBytesPerSec = tc.maxUploadBytesPerSec
 
 			// Start dmsvc.
 			resources := resourcesFromDeps(t, r, deps)
@@ -412,6 +464,7 @@ func TestArbitraryFileUpload(t *testing.T) {
 			test.That(t, tmpFile.Close(), test.ShouldBeNil)
 
 			// Advance the clock syncInterval so it tries to sync the files.
+			syncStart := time.Now()
 			mockClock.Add(syncInterval)
 
 			// Call manual sync.
@@ -456,9 +509,39 @@ func TestArbitraryFileUpload(t *testing.T) {
 				dataRequests := urs[1:]
 				var actData []byte
 				for _, d := range dataRequests {
+					test.That(t, d.GetMetadata(), test.ShouldBeNil)
 					actData = append(actData, d.GetFileContents().GetData()...)
 				}
+				uploadedLen := len(actData)
+
+				// Validate the metadata holds the checksum of the uploaded data.
+				checksumParam := actMD.GetMethodParameters()[datasync.FileUploadChecksumKey]
+				test.That(t, checksumParam, test.ShouldNotBeNil)
+				var actChecksum wrapperspb.StringValue
+				test.That(t, checksumParam.UnmarshalTo(&actChecksum), test.ShouldBeNil)
+				expChecksum := sha256.Sum256(actData)
+				test.That(t, actChecksum.GetValue(), test.ShouldEqual, hex.EncodeToString(expChecksum[:]))
+				if tc.expectCompressed {
+					test.That(t, uploadedLen, test.ShouldBeLessThan, len(fileContents))
+					gr, err := gzip.NewReader(bytes.NewReader(actData))
+					test.That(t, err, test.ShouldBeNil)
+					actData, err = io.ReadAll(gr)
+					test.That(t, err, test.ShouldBeNil)
+				}
 				test.That(t, actData, test.ShouldResemble, fileContents)
+				chunkSize := datasync.DefaultUploadChunkSize
+				if tc.uploadChunkSizeBytes != 0 {
+					chunkSize = tc.uploadChunkSizeBytes
+				}
+				test.That(t, len(dataRequests), test.ShouldEqual, (uploadedLen+chunkSize-1)/chunkSize)
+				if tc.maxUploadBytesPerSec != 0 {
+					// the first chunk is sent right away, the rest at the limited throughput
+					minUploadDuration := time.Duration(float64(uploadedLen-chunkSize) / float64(tc.maxUploadBytesPerSec) * float64(time.Second))
+					test.That(t, time.Since(syncStart), test.ShouldBeGreaterThanOrEqualTo, minUploadDuration)
+				}
+				for _, d := range dataRequests[:len(dataRequests)-1] {
+					test.That(t, len(d.GetFileContents().GetData()), test.ShouldEqual, chunkSize)
+				}
 
 				// Validate file no longer exists.
 				test.That(t, len(getAllFileInfos(additionalPathsDir)), test.ShouldEqual, 0)
@@ -919,9 +1002,10 @@ func (m *mockStreamingDCClient) CloseSend() error {
 
 func getTestSyncerConstructorMock(client mockDataSyncServiceClient) datasync.ManagerConstructor {
 	return func(identity string, _ v1.DataSyncServiceClient, logger logging.Logger,
-		viamCaptureDotDir string, maxSyncThreads int,
+		viamCaptureDotDir string, maxSyncThreads, uploadChunkSize int, compressArbitraryFiles bool, maxUploadBytesPerSec int,
 	) (datasync.Manager, error) {
-		return datasync.NewManager(identity, client, logger, viamCaptureDotDir, maxSyncThreads)
+		return datasync.NewManager(identity, client, logger, viamCaptureDotDir, maxSyncThreads, uploadChunkSize,
+			compressArbitraryFiles, maxUploadBytesPerSec)
 	}
 }
 
@@ -938,3 +1022,212 @@ func waitUntilNoFiles(dir string) {
 		files = getAllFileInfos(dir)
 	}
 }
+
+func TestGetAllFilesToSyncSkipsInProgressFiles(t *testing.T) {
+	clock = clk.New()
+	dir := t.TempDir()
+
+	// A collector capturing less often than every defaultFileLastModifiedMillis leaves its buffer's
+	// in progress file unmodified for longer than that between captures.
+	md := &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	buf := datacapture.NewBuffer(dir, md)
+	reading := &v1.SensorData{Metadata: &v1.SensorMetadata{}, Data: &v1.SensorData_Struct{Struct: &structpb.Struct{}}}
+	test.That(t, buf.Write(reading), test.ShouldBeNil)
+	inProgressPaths, err := filepath.Glob(filepath.Join(dir, "*"+datacapture.InProgressFileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, inProgressPaths, test.ShouldHaveLength, 1)
+	stale := time.Now().Add(-20 * time.Second)
+	test.That(t, os.Chtimes(inProgressPaths[0], stale, stale), test.ShouldBeNil)
+
+	// The file is neither synced nor touched, however long ago it was written to.
+	test.That(t, getAllFilesToSync(dir, defaultFileLastModifiedMillis, nil), test.ShouldBeEmpty)
+	_, err = os.Stat(inProgressPaths[0])
+	test.That(t, err, test.ShouldBeNil)
+
+	// So the buffer keeps writing to it & completes it, after which it is synced.
+	test.That(t, buf.Write(reading), test.ShouldBeNil)
+	test.That(t, buf.Flush(), test.ShouldBeNil)
+	completedPath := strings.TrimSuffix(inProgressPaths[0], datacapture.InProgressFileExt) + datacapture.FileExt
+	test.That(t, getAllFilesToSync(dir, defaultFileLastModifiedMillis, nil), test.ShouldResemble, []string{completedPath})
+	sd, err := datacapture.SensorDataFromFilePath(completedPath)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(sd), test.ShouldEqual, 2)
+}
+
+func TestRecoverInProgressFiles(t *testing.T) {
+	clock = clk.New()
+	logger := logging.NewTestLogger(t)
+	newInProgressFile := func(t *testing.T, dir string) string {
+		t.Helper()
+		f, err := datacapture.NewFile(dir, &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.WriteNext(&v1.SensorData{Metadata: &v1.SensorMetadata{}}), test.ShouldBeNil)
+		test.That(t, f.Flush(), test.ShouldBeNil)
+		return f.GetPath()
+	}
+
+	t.Run("a crash while writing syncs everything written before it", func(t *testing.T) {
+		dir := t.TempDir()
+		inProgressPath := newInProgressFile(t, dir)
+
+		recoverInProgressFiles(dir, logger)
+		completedPath := strings.TrimSuffix(inProgressPath, datacapture.InProgressFileExt) + datacapture.FileExt
+		test.That(t, getAllFilesToSync(dir, defaultFileLastModifiedMillis, nil), test.ShouldResemble, []string{completedPath})
+		_, err := os.Stat(inProgressPath)
+		test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+		sd, err := datacapture.SensorDataFromFilePath(completedPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(sd), test.ShouldEqual, 1)
+	})
+
+	t.Run("a crash during compression syncs only the uncompressed file", func(t *testing.T) {
+		dir := t.TempDir()
+		inProgressPath := newInProgressFile(t, dir)
+		withoutExt := strings.TrimSuffix(inProgressPath, datacapture.InProgressFileExt)
+		partialPath := withoutExt + datacapture.CompressedFileExt + datacapture.InProgressFileExt
+		test.That(t, os.WriteFile(partialPath, []byte("partial"), 0o600), test.ShouldBeNil)
+
+		recoverInProgressFiles(dir, logger)
+		toSync := getAllFilesToSync(dir, defaultFileLastModifiedMillis, nil)
+		test.That(t, toSync, test.ShouldResemble, []string{withoutExt + datacapture.FileExt})
+		_, err := os.Stat(partialPath)
+		test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+	})
+
+	t.Run("a crash during decompression syncs only the compressed file", func(t *testing.T) {
+		dir := t.TempDir()
+		compressedPath, err := datacapture.CompressFile(newInProgressFile(t, dir))
+		test.That(t, err, test.ShouldBeNil)
+		partialPath := strings.TrimSuffix(compressedPath, datacapture.CompressedFileExt) + datacapture.InProgressFileExt
+		test.That(t, os.WriteFile(partialPath, []byte("partial"), 0o600), test.ShouldBeNil)
+
+		recoverInProgressFiles(dir, logger)
+		toSync := getAllFilesToSync(dir, defaultFileLastModifiedMillis, nil)
+		test.That(t, toSync, test.ShouldResemble, []string{compressedPath})
+		_, err = os.Stat(partialPath)
+		test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+	})
+
+	t.Run("in progress files are recovered when their directory is first configured", func(t *testing.T) {
+		dir := t.TempDir()
+		inProgressPath := newInProgressFile(t, dir)
+
+		dmsvc, r := newTestDataManager(t)
+		defer dmsvc.Close(context.Background())
+		cfg, associations, deps := setupConfig(t, enabledBinaryCollectorConfigPath)
+		cfg.CaptureDisabled = true
+		cfg.ScheduledSyncDisabled = true
+		cfg.CaptureDir = dir
+		resources := resourcesFromDeps(t, r, deps)
+		err := dmsvc.Reconfigure(context.Background(), resources, resource.Config{
+			ConvertedAttributes:  cfg,
+			AssociatedAttributes: associations,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = os.Stat(strings.TrimSuffix(inProgressPath, datacapture.InProgressFileExt) + datacapture.FileExt)
+		test.That(t, err, test.ShouldBeNil)
+
+		// in progress files in a directory which has already been configured are left alone
+		inProgressPath = newInProgressFile(t, dir)
+		err = dmsvc.Reconfigure(context.Background(), resources, resource.Config{
+			ConvertedAttributes:  cfg,
+			AssociatedAttributes: associations,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = os.Stat(inProgressPath)
+		test.That(t, err, test.ShouldBeNil)
+	})
+}
+
+func TestGetAllFilesToSyncSkipsIgnoredFiles(t *testing.T) {
+	clock = clk.New()
+	dir := t.TempDir()
+	stale := time.Now().Add(-2 * defaultFileLastModifiedMillis * time.Millisecond)
+	for _, name := range []string{"log.txt", "scratch.tmp", "download.part", "recent.txt"} {
+		path := filepath.Join(dir, name)
+		test.That(t, os.WriteFile(path, []byte("moo"), 0o600), test.ShouldBeNil)
+		if name != "recent.txt" {
+			test.That(t, os.Chtimes(path, stale, stale), test.ShouldBeNil)
+		}
+	}
+	// Completed data capture files are synced even if they match a glob.
+	f, err := datacapture.NewFile(dir, &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+
+	toSync := getAllFilesToSync(dir, defaultFileLastModifiedMillis, []string{"*.tmp", "*.part", "*" + datacapture.FileExt})
+	sort.Strings(toSync)
+	completedPath := strings.TrimSuffix(f.GetPath(), datacapture.InProgressFileExt) + datacapture.FileExt
+	expected := []string{completedPath, filepath.Join(dir, "log.txt")}
+	sort.Strings(expected)
+	test.That(t, toSync, test.ShouldResemble, expected)
+
+	cfg := &Config{IgnoredFileGlobs: []string{"[.tmp"}}
+	_, err = cfg.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCompressedDataCaptureFileUpload(t *testing.T) {
+	mockClock := clk.NewMock()
+	clock = mockClock
+	captureDir := t.TempDir()
+
+	// Write a completed capture file and compress it.
+	md := &v1.DataCaptureMetadata{
+		ComponentType: "rdk:component:arm",
+		ComponentName: "arm1",
+		MethodName:    "EndPosition",
+		Type:          v1.DataType_DATA_TYPE_TABULAR_SENSOR,
+		FileExtension: ".dat",
+	}
+	f, err := datacapture.NewFile(captureDir, md)
+	test.That(t, err, test.ShouldBeNil)
+	reading := &v1.SensorData{Metadata: &v1.SensorMetadata{}, Data: &v1.SensorData_Struct{Struct: &structpb.Struct{}}}
+	test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	completedPath := strings.TrimSuffix(f.GetPath(), datacapture.InProgressFileExt) + datacapture.FileExt
+	contents, err := os.ReadFile(completedPath)
+	test.That(t, err, test.ShouldBeNil)
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(contents)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gz.Close(), test.ShouldBeNil)
+	test.That(t, os.WriteFile(completedPath+".gz", compressed.Bytes(), 0o600), test.ShouldBeNil)
+	test.That(t, os.Remove(completedPath), test.ShouldBeNil)
+
+	dmsvc, r := newTestDataManager(t)
+	defer dmsvc.Close(context.Background())
+	f2 := atomic.Bool{}
+	mockClient := mockDataSyncServiceClient{
+		succesfulDCRequests: make(chan *v1.DataCaptureUploadRequest, 100),
+		failedDCRequests:    make(chan *v1.DataCaptureUploadRequest, 100),
+		fail:                &f2,
+	}
+	dmsvc.SetSyncerConstructor(getTestSyncerConstructorMock(mockClient))
+	cfg, associations, deps := setupConfig(t, disabledTabularCollectorConfigPath)
+	cfg.ScheduledSyncDisabled = true
+	cfg.CaptureDir = captureDir
+	resources := resourcesFromDeps(t, r, deps)
+	err = dmsvc.Reconfigure(context.Background(), resources, resource.Config{
+		ConvertedAttributes:  cfg,
+		AssociatedAttributes: associations,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, dmsvc.Sync(context.Background(), nil), test.ShouldBeNil)
+
+	// The compressed file is uploaded with the metadata it contains.
+	select {
+	case <-time.After(time.Second * 3):
+		t.Fatalf("timed out waiting for sync request")
+	case req := <-mockClient.succesfulDCRequests:
+		test.That(t, req.GetMetadata().GetComponentName(), test.ShouldEqual, md.GetComponentName())
+		test.That(t, req.GetMetadata().GetMethodName(), test.ShouldEqual, md.GetMethodName())
+		test.That(t, req.GetMetadata().GetType(), test.ShouldEqual, v1.DataType_DATA_TYPE_TABULAR_SENSOR)
+		test.That(t, req.GetMetadata().GetFileExtension(), test.ShouldEqual, md.GetFileExtension())
+		test.That(t, len(req.GetSensorContents()), test.ShouldEqual, 1)
+	}
+	waitUntilNoFiles(captureDir)
+	test.That(t, len(getAllFileInfos(captureDir)), test.ShouldEqual, 0)
+}
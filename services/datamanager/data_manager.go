@@ -141,6 +141,9 @@ type DataCaptureConfig struct {
 	Disabled           bool              `json:"disabled"`
 	Tags               []string          `json:"tags,omitempty"`
 	CaptureDirectory   string            `json:"capture_directory"`
+	CompressFiles      bool              `json:"compress_files,omitempty"`
+	MaxTotalBytes      int64             `json:"max_total_bytes,omitempty"`
+	BatchBinaryData    bool              `json:"batch_binary_data,omitempty"`
 }
 
 // Equals checks if one capture config is equal to another.
@@ -153,7 +156,10 @@ func (c *DataCaptureConfig) Equals(other *DataCaptureConfig) bool {
 		c.Disabled == other.Disabled &&
 		slices.Compare(c.Tags, other.Tags) == 0 &&
 		reflect.DeepEqual(c.AdditionalParams, other.AdditionalParams) &&
-		c.CaptureDirectory == other.CaptureDirectory
+		c.CaptureDirectory == other.CaptureDirectory &&
+		c.CompressFiles == other.CompressFiles &&
+		c.MaxTotalBytes == other.MaxTotalBytes &&
+		c.BatchBinaryData == other.BatchBinaryData
 }
 
 // ShouldSyncKey is a special key we use within a modular sensor to pass a boolean
@@ -1,15 +1,42 @@
 package datacapture
 
 import (
+	"os"
+	"path/filepath"
+	"sort"
 	"sync"
+	"syscall"
 
+	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
+
+	"go.viam.com/rdk/logging"
 )
 
 // MaxFileSize is the maximum size in bytes of a data capture file.
 var MaxFileSize = int64(64 * 1024)
 
+var (
+	// ErrBufferBusy may be returned by a BufferedWriter's Write to indicate that it can't currently
+	// accept data. Collectors drop the reading and skip capturing until a Write succeeds rather than blocking.
+	ErrBufferBusy = errors.New("capture buffer busy")
+	// ErrBufferFull may be returned by a BufferedWriter's Write to indicate that it has no space for
+	// more data. It is handled by collectors in the same way as ErrBufferBusy. A Buffer returns it when
+	// the disk is full, or when it can't evict enough files to stay within its maximum total size.
+	ErrBufferFull = errors.New("capture buffer full")
+)
+
+// InProgressMarker marks files as in progress so they aren't deleted while they're being used, as the
+// datasync Manager does while syncing them.
+type InProgressMarker interface {
+	// MarkInProgress marks path as in progress, returning false if it already was.
+	MarkInProgress(path string) bool
+	// UnmarkInProgress unmarks path as in progress.
+	UnmarkInProgress(path string)
+}
+
 // BufferedWriter is a buffered, persistent queue of SensorData.
+// Write may return ErrBufferBusy or ErrBufferFull to apply backpressure to the caller.
 type BufferedWriter interface {
 	Write(item *v1.SensorData) error
 	Flush() error
@@ -18,29 +45,120 @@ type BufferedWriter interface {
 
 // Buffer is a persistent queue of SensorData backed by a series of datacapture.Files.
 type Buffer struct {
-	Directory string
-	MetaData  *v1.DataCaptureMetadata
-	nextFile  *File
-	lock      sync.Mutex
+	Directory  string
+	MetaData   *v1.DataCaptureMetadata
+	nextFile   *File
+	lock       sync.Mutex
+	fileNameFn FileNameFunc
+	compress   bool
+	// batchBinary makes binary data share size bounded files like tabular data
+	batchBinary bool
+
+	// maxTotalBytes bounds the size of the completed & in progress files in Directory, zero if it is unbounded
+	maxTotalBytes int64
+	// trackedBytes is the size of the files in Directory when it was last read plus the size of the files
+	// completed since. Files synced since then are still counted, so Directory is only read again, to
+	// correct it and evict files, once it exceeds maxTotalBytes.
+	trackedBytes int64
+	// tracking is set once Directory has been read to initialize trackedBytes
+	tracking bool
+	// full is set when the files in Directory exceed maxTotalBytes even after evicting all completed files
+	full   bool
+	inUse  InProgressMarker
+	logger logging.Logger
+}
+
+// BufferOption configures a Buffer.
+type BufferOption func(*Buffer)
+
+// WithFileNameFunc sets the function used to name each data capture file the Buffer creates,
+// e.g. to produce predictable names incorporating the component name and timestamp. Names
+// must not contain path separators; if a name collides with an existing capture file a
+// numeric suffix is appended. By default files are named with their creation timestamp.
+func WithFileNameFunc(fn FileNameFunc) BufferOption {
+	return func(b *Buffer) {
+		b.fileNameFn = fn
+	}
+}
+
+// WithCompression makes the Buffer gzip compress each data capture file, tabular or binary, once it
+// is complete, so completed files have the CompressedFileExt extension. They are decompressed
+// before being synced.
+func WithCompression() BufferOption {
+	return func(b *Buffer) {
+		b.compress = true
+	}
+}
+
+// WithBinaryBatching makes the Buffer write binary sensor data into MaxFileSize sized files, as it does
+// tabular data, rather than writing each binary reading to its own file. Each reading keeps its own
+// SensorMetadata, so can still be read individually with SensorDataFromFile.
+func WithBinaryBatching() BufferOption {
+	return func(b *Buffer) {
+		b.batchBinary = true
+	}
+}
+
+// WithMaxTotalBytes bounds the total size of the data capture files in the Buffer's Directory. Whenever
+// a file is completed and the bound is exceeded, the oldest completed files are deleted until the total is
+// a tenth below it, logging each deleted file to logger, so Directory is only read once a tenth of the
+// bound has been written. In progress files, and completed files that inUse fails to mark in progress as
+// they're being synced, are never deleted; if the bound can't be met without them, Write returns
+// ErrBufferFull until it can. The Buffer's own in progress file is completed first, so it can be deleted.
+// inUse may be nil.
+func WithMaxTotalBytes(maxTotalBytes int64, inUse InProgressMarker, logger logging.Logger) BufferOption {
+	return func(b *Buffer) {
+		b.maxTotalBytes = maxTotalBytes
+		b.inUse = inUse
+		b.logger = logger
+	}
 }
 
 // NewBuffer returns a new Buffer.
-func NewBuffer(dir string, md *v1.DataCaptureMetadata) *Buffer {
-	return &Buffer{
-		Directory: dir,
-		MetaData:  md,
+func NewBuffer(dir string, md *v1.DataCaptureMetadata, opts ...BufferOption) *Buffer {
+	b := &Buffer{
+		Directory:  dir,
+		MetaData:   md,
+		fileNameFn: getFileTimestampName,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
 }
 
-// Write writes item onto b. Binary sensor data is written to its own file.
-// Tabular data is written to disk in MaxFileSize sized files. Files that are still being written to are indicated
-// with the extension InProgressFileExt. Files that have finished being written to are indicated by FileExt.
+// Write writes item onto b. Binary sensor data is written to its own file, unless b batches binary data.
+// Tabular data, and binary data if b batches it, is written to disk in MaxFileSize sized files. Files that are still being written to are indicated
+// with the extension InProgressFileExt. Files that have finished being written to are indicated by FileExt, or by
+// CompressedFileExt if b compresses them.
+// Write returns ErrBufferFull without writing item if the disk is full or b is bounded and can't make room for it.
 func (b *Buffer) Write(item *v1.SensorData) error {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	if item.GetBinary() != nil {
-		binFile, err := NewFile(b.Directory, b.MetaData)
+	if b.full {
+		// b's own in progress file can't be evicted until it's completed, so it's completed before giving up
+		if b.nextFile != nil {
+			if err := b.completeNextFile(); err != nil {
+				return err
+			}
+		} else if err := b.evictOldestFiles(0); err != nil {
+			return err
+		}
+		if b.full {
+			return ErrBufferFull
+		}
+	}
+	err := b.write(item)
+	if errors.Is(err, syscall.ENOSPC) {
+		return errors.Wrap(ErrBufferFull, err.Error())
+	}
+	return err
+}
+
+func (b *Buffer) write(item *v1.SensorData) error {
+	if item.GetBinary() != nil && !b.batchBinary {
+		binFile, err := b.newFile()
 		if err != nil {
 			return err
 		}
@@ -50,20 +168,20 @@ func (b *Buffer) Write(item *v1.SensorData) error {
 		if err := binFile.Close(); err != nil {
 			return err
 		}
-		return nil
+		return b.evictOldestFiles(binFile.Size())
 	}
 
 	if b.nextFile == nil {
-		nextFile, err := NewFile(b.Directory, b.MetaData)
+		nextFile, err := b.newFile()
 		if err != nil {
 			return err
 		}
 		b.nextFile = nextFile
 	} else if b.nextFile.Size() > MaxFileSize {
-		if err := b.nextFile.Close(); err != nil {
+		if err := b.completeNextFile(); err != nil {
 			return err
 		}
-		nextFile, err := NewFile(b.Directory, b.MetaData)
+		nextFile, err := b.newFile()
 		if err != nil {
 			return err
 		}
@@ -73,6 +191,15 @@ func (b *Buffer) Write(item *v1.SensorData) error {
 	return b.nextFile.WriteNext(item)
 }
 
+func (b *Buffer) newFile() (*File, error) {
+	f, err := newFile(b.Directory, b.MetaData, b.fileNameFn)
+	if err != nil {
+		return nil, err
+	}
+	f.compress = b.compress
+	return f, nil
+}
+
 // Flush flushes all buffered data to disk and marks any in progress file as complete.
 func (b *Buffer) Flush() error {
 	b.lock.Lock()
@@ -80,14 +207,167 @@ func (b *Buffer) Flush() error {
 	if b.nextFile == nil {
 		return nil
 	}
+	return b.completeNextFile()
+}
+
+// completeNextFile closes b's in progress file, marking it as complete, and evicts files to make room for it.
+func (b *Buffer) completeNextFile() error {
 	if err := b.nextFile.Close(); err != nil {
 		return err
 	}
+	completedBytes := b.nextFile.Size()
 	b.nextFile = nil
-	return nil
+	return b.evictOldestFiles(completedBytes)
 }
 
 // Path returns the path to the directory containing the backing data capture files.
 func (b *Buffer) Path() string {
 	return b.Directory
 }
+
+// evictOldestFiles tracks a newly completed file of completedBytes, then if the tracked size exceeds b's
+// maxTotalBytes, or b is full, reads b's Directory and deletes the oldest completed files that aren't in use until
+// the total size of its files is a tenth below maxTotalBytes. It sets b.full if the total can't be brought
+// within maxTotalBytes.
+func (b *Buffer) evictOldestFiles(completedBytes int64) error {
+	if b.maxTotalBytes <= 0 {
+		return nil
+	}
+	b.trackedBytes += completedBytes
+	if b.tracking && !b.full && b.trackedBytes <= b.maxTotalBytes {
+		return nil
+	}
+
+	entries, err := os.ReadDir(b.Directory)
+	if err != nil {
+		return err
+	}
+	var inProgressBytes, total int64
+	var completed []os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != InProgressFileExt && ext != FileExt && !IsCompressedDataCaptureFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// the file may have been synced and deleted since the directory was read
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		total += info.Size()
+		if ext == InProgressFileExt {
+			inProgressBytes += info.Size()
+		} else {
+			completed = append(completed, info)
+		}
+	}
+	b.tracking = true
+	b.trackedBytes = total
+	// in progress files alone exceeding the bound can't be made room for by deleting completed ones
+	b.full = inProgressBytes > b.maxTotalBytes
+	if total <= b.maxTotalBytes || b.full {
+		return nil
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		if completed[i].ModTime().Equal(completed[j].ModTime()) {
+			return completed[i].Name() < completed[j].Name()
+		}
+		return completed[i].ModTime().Before(completed[j].ModTime())
+	})
+	lowWaterBytes := b.maxTotalBytes - b.maxTotalBytes/10
+	for _, info := range completed {
+		if total <= lowWaterBytes {
+			break
+		}
+		path := filepath.Join(b.Directory, info.Name())
+		removed, err := b.removeUnusedFile(path)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			continue
+		}
+		total -= info.Size()
+		b.logger.Warnw("deleted data capture file to stay within the maximum total size",
+			"file", path, "max_total_bytes", b.maxTotalBytes)
+	}
+	b.trackedBytes = total
+	b.full = total > b.maxTotalBytes
+	return nil
+}
+
+// removeUnusedFile removes the file at path unless b.inUse fails to mark it in progress. It returns whether
+// it removed the file, or the file had already been removed.
+func (b *Buffer) removeUnusedFile(path string) (bool, error) {
+	if b.inUse != nil {
+		if !b.inUse.MarkInProgress(path) {
+			return false, nil
+		}
+		defer b.inUse.UnmarkInProgress(path)
+	}
+	// the file may have been synced and deleted since the directory was read
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	return true, nil
+}
+
+// BufferUsage describes the disk space consumed by a Buffer's data capture files.
+type BufferUsage struct {
+	// InProgressBytes is the size of files that are still being written to.
+	InProgressBytes int64
+	// CompletedBytes is the size of files that have finished being written to.
+	CompletedBytes int64
+}
+
+// DiskUsage returns the total size in bytes of the data capture files in b's Directory.
+func (b *Buffer) DiskUsage() (int64, error) {
+	usage, err := b.Usage()
+	if err != nil {
+		return 0, err
+	}
+	return usage.InProgressBytes + usage.CompletedBytes, nil
+}
+
+// Usage returns the size in bytes of the in progress and completed data capture files in b's Directory.
+// Data that has been written to b but not yet flushed to disk is not included.
+func (b *Buffer) Usage() (BufferUsage, error) {
+	var usage BufferUsage
+	entries, err := os.ReadDir(b.Directory)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return usage, nil
+		}
+		return usage, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != InProgressFileExt && ext != FileExt && !IsCompressedDataCaptureFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// the file may have been synced and deleted since the directory was read
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return BufferUsage{}, err
+		}
+		if ext == InProgressFileExt {
+			usage.InProgressBytes += info.Size()
+		} else {
+			usage.CompletedBytes += info.Size()
+		}
+	}
+	return usage, nil
+}
@@ -1,14 +1,22 @@
 package datacapture
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/logging"
 )
 
 type structReading struct {
@@ -119,6 +127,302 @@ func TestCaptureQueue(t *testing.T) {
 	}
 }
 
+func TestBufferFileNameFunc(t *testing.T) {
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1", Type: v1.DataType_DATA_TYPE_BINARY_SENSOR}
+	// second resolution names collide when writing rapidly
+	fileNameFn := func(md *v1.DataCaptureMetadata, ts time.Time) string {
+		return md.GetComponentName() + "_" + ts.UTC().Format("20060102T150405")
+	}
+
+	t.Run("applies the template without collisions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		sut := NewBuffer(tmpDir, md, WithFileNameFunc(fileNameFn))
+		numWrites := 5
+		for i := 0; i < numWrites; i++ {
+			test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+		}
+
+		dcFiles, progFiles := getCaptureFiles(tmpDir)
+		test.That(t, len(progFiles), test.ShouldEqual, 0)
+		test.That(t, len(dcFiles), test.ShouldEqual, numWrites)
+		for _, f := range dcFiles {
+			test.That(t, filepath.Base(f), test.ShouldStartWith, "cam1_")
+			sd, err := SensorDataFromFilePath(f)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(sd), test.ShouldEqual, 1)
+		}
+	})
+
+	t.Run("rejects names with path separators", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		sut := NewBuffer(tmpDir, md, WithFileNameFunc(func(*v1.DataCaptureMetadata, time.Time) string {
+			return "../escape"
+		}))
+		test.That(t, sut.Write(binarySensorData), test.ShouldNotBeNil)
+		dcFiles, progFiles := getCaptureFiles(filepath.Dir(tmpDir))
+		test.That(t, len(dcFiles), test.ShouldEqual, 0)
+		test.That(t, len(progFiles), test.ShouldEqual, 0)
+	})
+}
+
+func TestBufferDiskUsage(t *testing.T) {
+	MaxFileSize = 1024
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1"}
+	sut := NewBuffer(tmpDir, md)
+
+	usage, err := sut.DiskUsage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, usage, test.ShouldEqual, 0)
+
+	// every capture file starts with its length delimited metadata, followed by length delimited readings
+	mdSize := int64(protowire.SizeBytes(proto.Size(md)))
+	binarySize := int64(protowire.SizeBytes(proto.Size(binarySensorData)))
+	structSize := int64(protowire.SizeBytes(proto.Size(structSensorData)))
+
+	numBinary := 3
+	for i := 0; i < numBinary; i++ {
+		test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	}
+	numStruct := 2
+	for i := 0; i < numStruct; i++ {
+		test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	}
+	// flush the buffered readings to disk without completing the in progress file
+	test.That(t, sut.nextFile.Flush(), test.ShouldBeNil)
+
+	expCompleted := int64(numBinary) * (mdSize + binarySize)
+	expInProgress := mdSize + int64(numStruct)*structSize
+	detailed, err := sut.Usage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, detailed.CompletedBytes, test.ShouldEqual, expCompleted)
+	test.That(t, detailed.InProgressBytes, test.ShouldEqual, expInProgress)
+	usage, err = sut.DiskUsage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, usage, test.ShouldEqual, expCompleted+expInProgress)
+
+	// files which aren't data capture files are not counted
+	test.That(t, os.WriteFile(filepath.Join(tmpDir, "other.txt"), []byte("hello"), 0o600), test.ShouldBeNil)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+	detailed, err = sut.Usage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, detailed.CompletedBytes, test.ShouldEqual, expCompleted+expInProgress)
+	test.That(t, detailed.InProgressBytes, test.ShouldEqual, 0)
+}
+
+func TestBufferCompression(t *testing.T) {
+	MaxFileSize = 1024
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1"}
+	sut := NewBuffer(tmpDir, md, WithCompression())
+
+	numBinary := 2
+	for i := 0; i < numBinary; i++ {
+		test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	}
+	numStruct := 3
+	for i := 0; i < numStruct; i++ {
+		test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	}
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+
+	// every file is compressed once complete & nothing else is left behind
+	entries, err := os.ReadDir(tmpDir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(entries), test.ShouldEqual, numBinary+1)
+	var compressedBytes int64
+	var binary, structs int
+	for _, entry := range entries {
+		path := filepath.Join(tmpDir, entry.Name())
+		test.That(t, IsCompressedDataCaptureFile(path), test.ShouldBeTrue)
+		info, err := entry.Info()
+		test.That(t, err, test.ShouldBeNil)
+		compressedBytes += info.Size()
+
+		// the compressed files decompress into the data that was written
+		decompressedPath, err := DecompressFile(path)
+		test.That(t, err, test.ShouldBeNil)
+		sd, err := SensorDataFromFilePath(decompressedPath)
+		test.That(t, err, test.ShouldBeNil)
+		for _, d := range sd {
+			if d.GetBinary() != nil {
+				test.That(t, d.GetBinary(), test.ShouldResemble, binarySensorData.GetBinary())
+				binary++
+			} else {
+				test.That(t, d.GetStruct().AsMap(), test.ShouldResemble, structSensorData.GetStruct().AsMap())
+				structs++
+			}
+		}
+		test.That(t, os.Remove(decompressedPath), test.ShouldBeNil)
+	}
+	test.That(t, binary, test.ShouldEqual, numBinary)
+	test.That(t, structs, test.ShouldEqual, numStruct)
+	test.That(t, compressedBytes, test.ShouldBeGreaterThan, 0)
+
+	// compressed files count towards the completed usage
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+	usage, err := sut.Usage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, usage.CompletedBytes, test.ShouldBeGreaterThan, 0)
+	test.That(t, usage.InProgressBytes, test.ShouldEqual, 0)
+}
+
+func TestBufferMaxTotalBytes(t *testing.T) {
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1"}
+	fileSize := int64(protowire.SizeBytes(proto.Size(md))) + int64(protowire.SizeBytes(proto.Size(binarySensorData)))
+	maxFiles := 3
+	logger, logs := logging.NewObservedTestLogger(t)
+	sut := NewBuffer(tmpDir, md, WithMaxTotalBytes(int64(maxFiles)*fileSize, nil, logger))
+
+	// an in progress file is never evicted
+	inProgress, err := newFile(tmpDir, md, getFileTimestampName)
+	test.That(t, err, test.ShouldBeNil)
+	defer inProgress.Close()
+	test.That(t, inProgress.WriteNext(binarySensorData), test.ShouldBeNil)
+	test.That(t, inProgress.Flush(), test.ShouldBeNil)
+
+	var written []string
+	numWrites := 6
+	for i := 0; i < numWrites; i++ {
+		before, _ := getCaptureFiles(tmpDir)
+		test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+		after, _ := getCaptureFiles(tmpDir)
+		for _, f := range after {
+			if !slices.Contains(before, f) {
+				written = append(written, f)
+			}
+		}
+		usage, err := sut.DiskUsage()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, usage, test.ShouldBeLessThanOrEqualTo, int64(maxFiles)*fileSize)
+	}
+	test.That(t, len(written), test.ShouldEqual, numWrites)
+
+	// the oldest completed files were evicted to make room for the in progress one & the newest ones
+	dcFiles, progFiles := getCaptureFiles(tmpDir)
+	test.That(t, progFiles, test.ShouldResemble, []string{inProgress.GetPath()})
+	test.That(t, dcFiles, test.ShouldResemble, written[numWrites-maxFiles+1:])
+	test.That(t, logs.FilterMessageSnippet("deleted data capture file").Len(), test.ShouldEqual, maxFiles+1)
+}
+
+func TestBufferMaxTotalBytesSkipsFilesInUse(t *testing.T) {
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1"}
+	fileSize := int64(protowire.SizeBytes(proto.Size(md))) + int64(protowire.SizeBytes(proto.Size(binarySensorData)))
+	inUse := &fakeInProgressMarker{inProgress: map[string]bool{}}
+	sut := NewBuffer(tmpDir, md, WithMaxTotalBytes(3*fileSize, inUse, logging.NewTestLogger(t)))
+
+	for i := 0; i < 3; i++ {
+		test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	}
+	syncing, _ := getCaptureFiles(tmpDir)
+	test.That(t, len(syncing), test.ShouldEqual, 3)
+	inUse.MarkInProgress(syncing[0])
+
+	// the file being synced is kept, and the next oldest evicted in its place
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	dcFiles, _ := getCaptureFiles(tmpDir)
+	test.That(t, len(dcFiles), test.ShouldEqual, 2)
+	test.That(t, dcFiles, test.ShouldContain, syncing[0])
+	test.That(t, dcFiles, test.ShouldNotContain, syncing[1])
+	test.That(t, dcFiles, test.ShouldNotContain, syncing[2])
+	test.That(t, inUse.inProgress, test.ShouldResemble, map[string]bool{syncing[0]: true})
+}
+
+func TestBufferFull(t *testing.T) {
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1"}
+	fileSize := int64(protowire.SizeBytes(proto.Size(md))) + int64(protowire.SizeBytes(proto.Size(binarySensorData)))
+	sut := NewBuffer(tmpDir, md, WithMaxTotalBytes(2*fileSize, nil, logging.NewTestLogger(t)))
+
+	// in progress files that alone exceed the bound can't be evicted to make room
+	inProgress, err := newFile(tmpDir, md, getFileTimestampName)
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 3; i++ {
+		test.That(t, inProgress.WriteNext(binarySensorData), test.ShouldBeNil)
+	}
+	test.That(t, inProgress.Flush(), test.ShouldBeNil)
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	dcFiles, _ := getCaptureFiles(tmpDir)
+	test.That(t, len(dcFiles), test.ShouldEqual, 1)
+
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeError, ErrBufferFull)
+	dcFiles, _ = getCaptureFiles(tmpDir)
+	test.That(t, len(dcFiles), test.ShouldEqual, 1)
+
+	// once the in progress file is completed it can be evicted, and writes succeed again
+	test.That(t, inProgress.Close(), test.ShouldBeNil)
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	usage, err := sut.DiskUsage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, usage, test.ShouldBeLessThanOrEqualTo, 2*fileSize)
+}
+
+func TestBufferFullOwnInProgressFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1"}
+	maxTotalBytes := int64(100)
+	sut := NewBuffer(tmpDir, md, WithMaxTotalBytes(maxTotalBytes, nil, logging.NewTestLogger(t)))
+
+	// the buffer's own in progress file alone exceeds the bound once a completed file makes it check
+	for sut.nextFile == nil || sut.nextFile.Size() <= maxTotalBytes {
+		test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	}
+	test.That(t, sut.nextFile.Flush(), test.ShouldBeNil)
+	test.That(t, sut.Write(binarySensorData), test.ShouldBeNil)
+	test.That(t, sut.full, test.ShouldBeTrue)
+
+	// so it's completed to be evicted rather than every write failing with ErrBufferFull
+	test.That(t, sut.Write(structSensorData), test.ShouldBeNil)
+	test.That(t, sut.full, test.ShouldBeFalse)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+	usage, err := sut.DiskUsage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, usage, test.ShouldBeLessThanOrEqualTo, maxTotalBytes)
+}
+
+func TestBufferBinaryBatching(t *testing.T) {
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1", Type: v1.DataType_DATA_TYPE_BINARY_SENSOR}
+	numWrites := 5
+	var written []*v1.SensorData
+	for i := 0; i < numWrites; i++ {
+		written = append(written, &v1.SensorData{
+			Metadata: &v1.SensorMetadata{TimeRequested: timestamppb.New(time.Unix(int64(i+1), 0))},
+			Data:     &v1.SensorData_Binary{Binary: []byte(fmt.Sprintf("image %d", i))},
+		})
+	}
+	// each file fits 3 readings before exceeding MaxFileSize
+	MaxFileSize = int64(protowire.SizeBytes(proto.Size(md))) + 2*int64(protowire.SizeBytes(proto.Size(written[0])))
+	sut := NewBuffer(tmpDir, md, WithBinaryBatching())
+	for _, sd := range written {
+		test.That(t, sut.Write(sd), test.ShouldBeNil)
+	}
+
+	// binary readings share size bounded files
+	dcFiles, progFiles := getCaptureFiles(tmpDir)
+	test.That(t, len(dcFiles), test.ShouldEqual, 1)
+	test.That(t, len(progFiles), test.ShouldEqual, 1)
+	test.That(t, sut.Flush(), test.ShouldBeNil)
+	dcFiles, progFiles = getCaptureFiles(tmpDir)
+	test.That(t, len(dcFiles), test.ShouldEqual, 2)
+	test.That(t, len(progFiles), test.ShouldEqual, 0)
+
+	// each reading can be read back individually along with its own metadata
+	var read []*v1.SensorData
+	for _, path := range dcFiles {
+		sd, err := SensorDataFromFilePath(path)
+		test.That(t, err, test.ShouldBeNil)
+		read = append(read, sd...)
+	}
+	test.That(t, len(read), test.ShouldEqual, numWrites)
+	for i, sd := range read {
+		test.That(t, proto.Equal(sd, written[i]), test.ShouldBeTrue)
+	}
+}
+
 //nolint
 func getCaptureFiles(dir string) (dcFiles, progFiles []string) {
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -138,3 +442,19 @@ func getCaptureFiles(dir string) (dcFiles, progFiles []string) {
 	})
 	return dcFiles, progFiles
 }
+
+type fakeInProgressMarker struct {
+	inProgress map[string]bool
+}
+
+func (m *fakeInProgressMarker) MarkInProgress(path string) bool {
+	if m.inProgress[path] {
+		return false
+	}
+	m.inProgress[path] = true
+	return true
+}
+
+func (m *fakeInProgressMarker) UnmarkInProgress(path string) {
+	delete(m.inProgress, path)
+}
@@ -3,6 +3,7 @@ package datacapture
 
 import (
 	"bufio"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
@@ -13,7 +14,9 @@ import (
 
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -26,6 +29,8 @@ import (
 const (
 	InProgressFileExt = ".prog"
 	FileExt           = ".capture"
+	// CompressedFileExt defines the file extension for gzip compressed Viam data capture files.
+	CompressedFileExt = FileExt + ".gz"
 	readImage         = "ReadImage"
 	// GetImages is used for getting simultaneous images from different imagers.
 	GetImages      = "GetImages"
@@ -46,6 +51,8 @@ type File struct {
 	writer   *bufio.Writer
 	size     int64
 	metadata *v1.DataCaptureMetadata
+	// compress is whether the file is gzip compressed when it is closed
+	compress bool
 
 	initialReadOffset int64
 	readOffset        int64
@@ -82,12 +89,24 @@ func ReadFile(f *os.File) (*File, error) {
 	return &ret, nil
 }
 
+// FileNameFunc returns the name, without a directory or extension, of a data capture file
+// created at time t for the given metadata.
+type FileNameFunc func(md *v1.DataCaptureMetadata, t time.Time) string
+
+// maxFileNameCollisions is the number of suffixed names tried before giving up on creating a file.
+const maxFileNameCollisions = 1000
+
 // NewFile creates a new File with the specified md in the specified directory.
 func NewFile(dir string, md *v1.DataCaptureMetadata) (*File, error) {
-	fileName := FilePathWithReplacedReservedChars(
-		filepath.Join(dir, getFileTimestampName()) + InProgressFileExt)
-	//nolint:gosec
-	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0o600)
+	return newFile(dir, md, getFileTimestampName)
+}
+
+func newFile(dir string, md *v1.DataCaptureMetadata, fileNameFn FileNameFunc) (*File, error) {
+	name := fileNameFn(md, time.Now())
+	if err := validateFileName(name); err != nil {
+		return nil, err
+	}
+	f, err := createUniqueFile(dir, name)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +121,7 @@ func NewFile(dir string, md *v1.DataCaptureMetadata) (*File, error) {
 		writer:            bufio.NewWriter(f),
 		file:              f,
 		size:              int64(n),
+		metadata:          md,
 		initialReadOffset: int64(n),
 		readOffset:        int64(n),
 		writeOffset:       int64(n),
@@ -178,21 +198,73 @@ func (f *File) GetPath() string {
 	return f.path
 }
 
-// Close closes the file.
+// Close closes the file and marks it as complete.
+// All data is persisted to disk before the file is atomically renamed to have the
+// FileExt extension, so a completed file is never observed with partial contents.
+// Files created to be compressed are instead compressed into a file with the CompressedFileExt extension.
 func (f *File) Close() error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 	if err := f.writer.Flush(); err != nil {
 		return err
 	}
+	// Files opened for reading with ReadFile may already be complete.
+	if filepath.Ext(f.file.Name()) != InProgressFileExt {
+		return f.file.Close()
+	}
+	if err := f.file.Sync(); err != nil {
+		return multierr.Combine(err, f.file.Close())
+	}
+	if err := f.file.Close(); err != nil {
+		return err
+	}
 
-	// Rename file to indicate that it is done being written.
-	withoutExt := strings.TrimSuffix(f.file.Name(), filepath.Ext(f.file.Name()))
-	newName := withoutExt + FileExt
-	if err := os.Rename(f.file.Name(), newName); err != nil {
+	if f.compress {
+		_, err := CompressFile(f.file.Name())
 		return err
 	}
-	return f.file.Close()
+	// Rename file to indicate that it is done being written.
+	_, err := MarkFileComplete(f.file.Name())
+	return err
+}
+
+// MarkFileComplete atomically renames the in progress data capture file at path to have the
+// FileExt extension, or the CompressedFileExt extension if it is being compressed, and returns
+// the new path. It is used both when a File is closed and to recover in progress files left
+// behind if the process exited before closing them.
+func MarkFileComplete(path string) (string, error) {
+	if filepath.Ext(path) != InProgressFileExt {
+		return "", errors.Errorf("%s is not an in progress data capture file", path)
+	}
+	newPath := strings.TrimSuffix(path, InProgressFileExt)
+	if !IsCompressedDataCaptureFile(newPath) {
+		newPath += FileExt
+	}
+	if err := os.Rename(path, newPath); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+// RecoverInProgressFile recovers the in progress data capture file at path left behind if the
+// process exited before it was completed, returning the path of the completed file to sync.
+// Files left behind by CompressFile or DecompressFile whose contents are held in full by another
+// file are removed instead, so the data isn't synced twice, in which case "" is returned.
+func RecoverInProgressFile(path string) (string, error) {
+	if filepath.Ext(path) != InProgressFileExt {
+		return "", errors.Errorf("%s is not an in progress data capture file", path)
+	}
+	withoutExt := strings.TrimSuffix(path, InProgressFileExt)
+	// an interrupted compression leaves its source, which is recovered instead
+	supersededBy := strings.TrimSuffix(withoutExt, CompressedFileExt) + InProgressFileExt
+	if !IsCompressedDataCaptureFile(withoutExt) {
+		// a compression interrupted after completing, or a decompression, leaves the compressed file
+		supersededBy = withoutExt + CompressedFileExt
+	}
+	if _, err := os.Stat(supersededBy); err == nil {
+		return "", os.Remove(path)
+	}
+	return MarkFileComplete(path)
 }
 
 // Delete deletes the file.
@@ -236,10 +308,148 @@ func IsDataCaptureFile(f *os.File) bool {
 	return filepath.Ext(f.Name()) == FileExt || filepath.Ext(f.Name()) == InProgressFileExt
 }
 
+// IsCompressedDataCaptureFile returns whether or not path is a gzip compressed data capture file.
+func IsCompressedDataCaptureFile(path string) bool {
+	return strings.HasSuffix(path, CompressedFileExt)
+}
+
+// DecompressFile decompresses the gzip compressed data capture file at path into a completed data
+// capture file in the same directory, removes the compressed file, and returns the path of the
+// decompressed file. The decompressed file is only marked complete once it has been fully written.
+func DecompressFile(path string) (string, error) {
+	if !IsCompressedDataCaptureFile(path) {
+		return "", errors.Errorf("%s is not a compressed data capture file", path)
+	}
+	withoutExt := strings.TrimSuffix(path, CompressedFileExt)
+	if _, err := os.Stat(withoutExt + FileExt); err == nil {
+		return "", errors.Errorf("cannot decompress %s, %s already exists", path, withoutExt+FileExt)
+	}
+
+	//nolint:gosec
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer goutils.UncheckedErrorFunc(in.Close)
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read compressed data capture file %s", path)
+	}
+
+	inProgressPath := withoutExt + InProgressFileExt
+	//nolint:gosec
+	out, err := os.OpenFile(inProgressPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	//nolint:gosec
+	if _, err := io.Copy(out, gz); err != nil {
+		return "", multierr.Combine(
+			errors.Wrapf(err, "failed to decompress %s", path),
+			out.Close(),
+			os.Remove(inProgressPath))
+	}
+	if err := multierr.Combine(gz.Close(), out.Sync(), out.Close()); err != nil {
+		return "", multierr.Combine(err, os.Remove(inProgressPath))
+	}
+
+	completedPath, err := MarkFileComplete(inProgressPath)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Remove(path); err != nil {
+		return "", err
+	}
+	return completedPath, nil
+}
+
+// CompressFile gzip compresses the in progress data capture file at path into a completed compressed
+// data capture file in the same directory, removes the in progress file, and returns the path of the
+// compressed file. It is the counterpart of DecompressFile. The compressed file is written with the
+// InProgressFileExt extension, so it is only synced once it has been fully written and renamed.
+func CompressFile(path string) (string, error) {
+	if filepath.Ext(path) != InProgressFileExt {
+		return "", errors.Errorf("%s is not an in progress data capture file", path)
+	}
+	compressedPath := strings.TrimSuffix(path, InProgressFileExt) + CompressedFileExt
+	if _, err := os.Stat(compressedPath); err == nil {
+		return "", errors.Errorf("cannot compress %s, %s already exists", path, compressedPath)
+	}
+
+	//nolint:gosec
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer goutils.UncheckedErrorFunc(in.Close)
+
+	inProgressPath := compressedPath + InProgressFileExt
+	//nolint:gosec
+	out, err := os.OpenFile(inProgressPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		return "", multierr.Combine(
+			errors.Wrapf(err, "failed to compress %s", path),
+			out.Close(),
+			os.Remove(inProgressPath))
+	}
+	if err := multierr.Combine(gz.Close(), out.Sync(), out.Close()); err != nil {
+		return "", multierr.Combine(err, os.Remove(inProgressPath))
+	}
+
+	if err := os.Rename(inProgressPath, compressedPath); err != nil {
+		return "", err
+	}
+	if err := os.Remove(path); err != nil {
+		return "", err
+	}
+	return compressedPath, nil
+}
+
 // Create a filename based on the current time.
-func getFileTimestampName() string {
+func getFileTimestampName(_ *v1.DataCaptureMetadata, t time.Time) string {
 	// RFC3339Nano is a standard time format e.g. 2006-01-02T15:04:05Z07:00.
-	return time.Now().Format(time.RFC3339Nano)
+	return t.Format(time.RFC3339Nano)
+}
+
+// validateFileName returns an error if name can't be used as the name of a data capture file.
+func validateFileName(name string) error {
+	if name == "" || name == "." || name == ".." {
+		return errors.Errorf("invalid data capture file name %q", name)
+	}
+	if strings.ContainsAny(name, "/\\\x00") {
+		return errors.Errorf("data capture file name %q must not contain path separators or null characters", name)
+	}
+	return nil
+}
+
+// createUniqueFile creates a new in progress file named name in dir. If a capture file with that
+// name already exists, either in progress or completed, a numeric suffix is appended so that an
+// existing file is never appended to or overwritten.
+func createUniqueFile(dir, name string) (*os.File, error) {
+	for i := 0; i < maxFileNameCollisions; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s_%d", name, i)
+		}
+		path := FilePathWithReplacedReservedChars(filepath.Join(dir, candidate))
+		if _, err := os.Stat(path + FileExt); err == nil {
+			continue
+		}
+		if _, err := os.Stat(path + CompressedFileExt); err == nil {
+			continue
+		}
+		//nolint:gosec
+		f, err := os.OpenFile(path+InProgressFileExt, os.O_APPEND|os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, errors.Errorf("could not create a unique data capture file named %q in %s", name, dir)
 }
 
 // TODO DATA-246: Implement this in some more robust, programmatic way.
@@ -317,6 +527,55 @@ func SensorDataFromFile(f *File) ([]*v1.SensorData, error) {
 	return ret, nil
 }
 
+// FileReader iterates over the SensorData in a data capture file without going through the sync path,
+// so that written files can be validated or reprocessed directly.
+type FileReader struct {
+	file       *File
+	inProgress bool
+}
+
+// NewFileReader opens the data capture file at path for reading. Both completed (FileExt) and
+// in progress (InProgressFileExt) files are supported. Note that data buffered by a writer which
+// has not yet been flushed to disk will not be visible when reading an in progress file.
+func NewFileReader(path string) (*FileReader, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	dcFile, err := ReadFile(f)
+	if err != nil {
+		return nil, multierr.Combine(err, f.Close())
+	}
+	return &FileReader{file: dcFile, inProgress: filepath.Ext(path) == InProgressFileExt}, nil
+}
+
+// MetaData returns the DataCaptureMetadata of the file.
+func (r *FileReader) MetaData() *v1.DataCaptureMetadata {
+	return r.file.ReadMetadata()
+}
+
+// Next returns the next SensorData in the file, or io.EOF once all readings have been read.
+// A truncated trailing reading, which can occur when a file is still being written to or
+// if a robot is killed uncleanly during a write, is treated as the end of the file.
+func (r *FileReader) Next() (*v1.SensorData, error) {
+	next, err := r.file.ReadNext()
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, io.EOF
+	}
+	return next, err
+}
+
+// InProgress returns whether the file is still being written to.
+func (r *FileReader) InProgress() bool {
+	return r.inProgress
+}
+
+// Close closes the underlying file.
+func (r *FileReader) Close() error {
+	return r.file.file.Close()
+}
+
 // FilePathWithReplacedReservedChars returns the filepath with substitutions
 // for reserved characters.
 func FilePathWithReplacedReservedChars(filepath string) string {
@@ -1,8 +1,15 @@
 package datacapture
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"google.golang.org/protobuf/types/known/structpb"
@@ -115,3 +122,213 @@ func TestReadCorruptedFile(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(sd), test.ShouldEqual, numReadings)
 }
+
+func TestFileReader(t *testing.T) {
+	md := &v1.DataCaptureMetadata{
+		ComponentName: "arm1",
+		MethodName:    "EndPosition",
+		Type:          v1.DataType_DATA_TYPE_TABULAR_SENSOR,
+	}
+
+	readAll := func(t *testing.T, r *FileReader) []*v1.SensorData {
+		t.Helper()
+		var ret []*v1.SensorData
+		for {
+			next, err := r.Next()
+			if errors.Is(err, io.EOF) {
+				return ret
+			}
+			test.That(t, err, test.ShouldBeNil)
+			ret = append(ret, next)
+		}
+	}
+
+	t.Run("reads completed files written by a Buffer", func(t *testing.T) {
+		dir := t.TempDir()
+		b := NewBuffer(dir, md)
+		numReadings := 5
+		for i := 0; i < numReadings; i++ {
+			test.That(t, b.Write(structSensorData), test.ShouldBeNil)
+		}
+		test.That(t, b.Flush(), test.ShouldBeNil)
+
+		completeFiles, progFiles := getCaptureFiles(dir)
+		test.That(t, len(progFiles), test.ShouldEqual, 0)
+		test.That(t, len(completeFiles), test.ShouldBeGreaterThan, 0)
+
+		var readings []*v1.SensorData
+		for _, path := range completeFiles {
+			r, err := NewFileReader(path)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, r.InProgress(), test.ShouldBeFalse)
+			test.That(t, r.MetaData().String(), test.ShouldEqual, md.String())
+			readings = append(readings, readAll(t, r)...)
+			test.That(t, r.Close(), test.ShouldBeNil)
+		}
+		test.That(t, len(readings), test.ShouldEqual, numReadings)
+		for _, reading := range readings {
+			test.That(t, reading.GetStruct(), test.ShouldResemble, structSensorData.GetStruct())
+		}
+	})
+
+	t.Run("reads in progress files up to the last complete reading", func(t *testing.T) {
+		f, err := NewFile(t.TempDir(), md)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.WriteNext(structSensorData), test.ShouldBeNil)
+		test.That(t, f.WriteNext(structSensorData), test.ShouldBeNil)
+		// simulate a partially written reading
+		_, err = f.writer.Write([]byte{0x10, 0x01})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.Flush(), test.ShouldBeNil)
+		test.That(t, filepath.Ext(f.GetPath()), test.ShouldEqual, InProgressFileExt)
+
+		r, err := NewFileReader(f.GetPath())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, r.InProgress(), test.ShouldBeTrue)
+		test.That(t, r.MetaData().String(), test.ShouldEqual, md.String())
+		test.That(t, len(readAll(t, r)), test.ShouldEqual, 2)
+		test.That(t, r.Close(), test.ShouldBeNil)
+		test.That(t, f.Close(), test.ShouldBeNil)
+	})
+
+	t.Run("rejects files that are not data capture files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "not_capture.txt")
+		f, err := os.Create(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.Close(), test.ShouldBeNil)
+		_, err = NewFileReader(path)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestDecompressFile(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "arm1", Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	f, err := NewFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	reading := &v1.SensorData{Metadata: &v1.SensorMetadata{}, Data: &v1.SensorData_Struct{Struct: &structpb.Struct{}}}
+	test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	completedPath := strings.TrimSuffix(f.GetPath(), InProgressFileExt) + FileExt
+
+	contents, err := os.ReadFile(completedPath)
+	test.That(t, err, test.ShouldBeNil)
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(contents)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, gz.Close(), test.ShouldBeNil)
+	compressedPath := completedPath + ".gz"
+	test.That(t, IsCompressedDataCaptureFile(compressedPath), test.ShouldBeTrue)
+	test.That(t, IsCompressedDataCaptureFile(completedPath), test.ShouldBeFalse)
+	test.That(t, os.WriteFile(compressedPath, compressed.Bytes(), 0o600), test.ShouldBeNil)
+
+	t.Run("fails if the decompressed file already exists", func(t *testing.T) {
+		_, err := DecompressFile(compressedPath)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	test.That(t, os.Remove(completedPath), test.ShouldBeNil)
+	decompressedPath, err := DecompressFile(compressedPath)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, decompressedPath, test.ShouldEqual, completedPath)
+	_, err = os.Stat(compressedPath)
+	test.That(t, errors.Is(err, os.ErrNotExist), test.ShouldBeTrue)
+
+	r, err := NewFileReader(decompressedPath)
+	test.That(t, err, test.ShouldBeNil)
+	defer r.Close()
+	test.That(t, r.MetaData().GetComponentName(), test.ShouldEqual, "arm1")
+	next, err := r.Next()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, next.GetStruct(), test.ShouldNotBeNil)
+	_, err = r.Next()
+	test.That(t, errors.Is(err, io.EOF), test.ShouldBeTrue)
+}
+
+func TestRecoverInProgressFile(t *testing.T) {
+	md := &v1.DataCaptureMetadata{ComponentName: "arm1", Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	reading := &v1.SensorData{Metadata: &v1.SensorMetadata{}, Data: &v1.SensorData_Struct{Struct: &structpb.Struct{}}}
+	// newInProgressFile simulates a crash by leaving behind an in progress file that is never closed.
+	newInProgressFile := func(t *testing.T, dir string) string {
+		t.Helper()
+		f, err := NewFile(dir, md)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, f.WriteNext(reading), test.ShouldBeNil)
+		test.That(t, f.Flush(), test.ShouldBeNil)
+		return f.GetPath()
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	t.Run("marks an in progress file complete", func(t *testing.T) {
+		inProgressPath := newInProgressFile(t, t.TempDir())
+		completedPath, err := RecoverInProgressFile(inProgressPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, completedPath, test.ShouldEqual, strings.TrimSuffix(inProgressPath, InProgressFileExt)+FileExt)
+		test.That(t, exists(inProgressPath), test.ShouldBeFalse)
+		sd, err := SensorDataFromFilePath(completedPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(sd), test.ShouldEqual, 1)
+	})
+
+	t.Run("removes the partial output of a crash during compression & recovers its source", func(t *testing.T) {
+		inProgressPath := newInProgressFile(t, t.TempDir())
+		withoutExt := strings.TrimSuffix(inProgressPath, InProgressFileExt)
+		partialPath := withoutExt + CompressedFileExt + InProgressFileExt
+		test.That(t, os.WriteFile(partialPath, []byte("partial"), 0o600), test.ShouldBeNil)
+
+		completedPath, err := RecoverInProgressFile(partialPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, completedPath, test.ShouldBeEmpty)
+		test.That(t, exists(partialPath), test.ShouldBeFalse)
+
+		completedPath, err = RecoverInProgressFile(inProgressPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, completedPath, test.ShouldEqual, withoutExt+FileExt)
+	})
+
+	t.Run("removes the source of a compression which completed before a crash", func(t *testing.T) {
+		inProgressPath := newInProgressFile(t, t.TempDir())
+		withoutExt := strings.TrimSuffix(inProgressPath, InProgressFileExt)
+		test.That(t, os.WriteFile(withoutExt+CompressedFileExt, []byte("compressed"), 0o600), test.ShouldBeNil)
+
+		completedPath, err := RecoverInProgressFile(inProgressPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, completedPath, test.ShouldBeEmpty)
+		test.That(t, exists(inProgressPath), test.ShouldBeFalse)
+		test.That(t, exists(withoutExt+CompressedFileExt), test.ShouldBeTrue)
+	})
+
+	t.Run("removes the partial output of a crash during decompression", func(t *testing.T) {
+		compressedPath, err := CompressFile(newInProgressFile(t, t.TempDir()))
+		test.That(t, err, test.ShouldBeNil)
+
+		// DecompressFile writes the decompressed file in progress beside the compressed file
+		partialPath := strings.TrimSuffix(compressedPath, CompressedFileExt) + InProgressFileExt
+		test.That(t, os.WriteFile(partialPath, []byte("partial"), 0o600), test.ShouldBeNil)
+
+		completedPath, err := RecoverInProgressFile(partialPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, completedPath, test.ShouldBeEmpty)
+		test.That(t, exists(partialPath), test.ShouldBeFalse)
+
+		// the compressed file is left to be synced
+		decompressedPath, err := DecompressFile(compressedPath)
+		test.That(t, err, test.ShouldBeNil)
+		sd, err := SensorDataFromFilePath(decompressedPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(sd), test.ShouldEqual, 1)
+	})
+
+	t.Run("marks a compressed in progress file complete as a compressed file", func(t *testing.T) {
+		dir := t.TempDir()
+		inProgressPath := filepath.Join(dir, "data"+CompressedFileExt+InProgressFileExt)
+		test.That(t, os.WriteFile(inProgressPath, []byte("compressed"), 0o600), test.ShouldBeNil)
+		completedPath, err := RecoverInProgressFile(inProgressPath)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, completedPath, test.ShouldEqual, filepath.Join(dir, "data"+CompressedFileExt))
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"go.uber.org/atomic"
 	v1 "go.viam.com/api/app/datasync/v1"
 	goutils "go.viam.com/utils"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -57,6 +58,11 @@ type syncer struct {
 	cancelCtx         context.Context
 	cancelFunc        func()
 	arbitraryFileTags []string
+	uploadChunkSize   int
+	// uploadLimiter limits the throughput of streamed uploads, nil if it is unlimited
+	uploadLimiter *rate.Limiter
+	// compressArbitraryFiles is whether arbitrary files are gzip compressed before upload
+	compressArbitraryFiles bool
 
 	progressLock sync.Mutex
 	inProgress   map[string]bool
@@ -72,25 +78,39 @@ type syncer struct {
 
 // ManagerConstructor is a function for building a Manager.
 type ManagerConstructor func(identity string, client v1.DataSyncServiceClient, logger logging.Logger,
-	captureDir string, maxSyncThreadsConfig int) (Manager, error)
-
-// NewManager returns a new syncer.
+	captureDir string, maxSyncThreadsConfig, uploadChunkSize int, compressArbitraryFiles bool, maxUploadBytesPerSec int,
+) (Manager, error)
+
+// NewManager returns a new syncer. uploadChunkSize is the size in bytes of the data included in each
+// message when a file is uploaded in chunks, and must be greater than 0. If compressArbitraryFiles is
+// set, arbitrary files are gzip compressed before upload, unless they are already compressed.
+// maxUploadBytesPerSec limits the throughput of the file contents streamed over the connection, zero
+// meaning unlimited.
 func NewManager(identity string, client v1.DataSyncServiceClient, logger logging.Logger,
-	captureDir string, maxSyncThreads int,
+	captureDir string, maxSyncThreads, uploadChunkSize int, compressArbitraryFiles bool, maxUploadBytesPerSec int,
 ) (Manager, error) {
+	if uploadChunkSize <= 0 {
+		return nil, ErrInvalidUploadChunkSize
+	}
+	if maxUploadBytesPerSec < 0 {
+		return nil, ErrInvalidMaxUploadBytesPerSec
+	}
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 	logger.Debugf("Making new syncer with %d max threads", maxSyncThreads)
 	ret := syncer{
-		partID:             identity,
-		client:             client,
-		logger:             logger,
-		cancelCtx:          cancelCtx,
-		cancelFunc:         cancelFunc,
-		arbitraryFileTags:  []string{},
-		inProgress:         make(map[string]bool),
-		syncErrs:           make(chan error, 10),
-		syncRoutineTracker: make(chan struct{}, maxSyncThreads),
-		captureDir:         captureDir,
+		partID:                 identity,
+		client:                 client,
+		logger:                 logger,
+		cancelCtx:              cancelCtx,
+		cancelFunc:             cancelFunc,
+		arbitraryFileTags:      []string{},
+		uploadChunkSize:        uploadChunkSize,
+		compressArbitraryFiles: compressArbitraryFiles,
+		uploadLimiter:          newUploadLimiter(maxUploadBytesPerSec, uploadChunkSize),
+		inProgress:             make(map[string]bool),
+		syncErrs:               make(chan error, 10),
+		syncRoutineTracker:     make(chan struct{}, maxSyncThreads),
+		captureDir:             captureDir,
 	}
 	ret.logRoutine.Add(1)
 	goutils.PanicCapturingGo(func() {
@@ -145,6 +165,25 @@ func (s *syncer) SyncFile(path string) {
 					return
 				}
 				defer s.UnmarkInProgress(path)
+
+				// Compressed data capture files are decompressed and then uploaded like any other
+				// data capture file, using the metadata they contain.
+				if datacapture.IsCompressedDataCaptureFile(path) {
+					decompressedPath, err := datacapture.DecompressFile(path)
+					if err != nil {
+						s.syncErrs <- errors.Wrap(err, "error decompressing data capture file")
+						if err := moveFailedData(path, s.captureDir); err != nil {
+							s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error moving corrupted data %s", path))
+						}
+						return
+					}
+					if !s.MarkInProgress(decompressedPath) {
+						return
+					}
+					defer s.UnmarkInProgress(decompressedPath)
+					path = decompressedPath
+				}
+
 				//nolint:gosec
 				f, err := os.Open(path)
 				if err != nil {
@@ -179,10 +218,11 @@ func (s *syncer) SyncFile(path string) {
 }
 
 func (s *syncer) syncDataCaptureFile(f *datacapture.File) {
+	var uploaded int
 	uploadErr := exponentialRetry(
 		s.cancelCtx,
 		func(ctx context.Context) error {
-			err := uploadDataCaptureFile(ctx, s.client, f, s.partID)
+			err := uploadDataCaptureFile(ctx, s.client, f, s.partID, s.uploadChunkSize, s.uploadLimiter, &uploaded)
 			if err != nil {
 				s.syncErrs <- errors.Wrap(err, fmt.Sprintf("error uploading file %s", f.GetPath()))
 			}
@@ -212,7 +252,8 @@ func (s *syncer) syncArbitraryFile(f *os.File) {
 	uploadErr := exponentialRetry(
 		s.cancelCtx,
 		func(ctx context.Context) error {
-			uploadErr := uploadArbitraryFile(ctx, s.client, f, s.partID, s.arbitraryFileTags)
+			uploadErr := uploadArbitraryFile(ctx, s.client, f, s.partID, s.arbitraryFileTags, s.uploadChunkSize,
+				s.compressArbitraryFiles, s.uploadLimiter)
 			if uploadErr != nil {
 				s.syncErrs <- errors.Wrap(uploadErr, fmt.Sprintf("error uploading file %s", f.Name()))
 			}
@@ -1,19 +1,31 @@
 package datasync
 
 import (
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	clk "github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
-// UploadChunkSize defines the size of the data included in each message of a FileUpload stream.
-var UploadChunkSize = 64 * 1024
+// DefaultUploadChunkSize is the default size in bytes of the data included in each message of a
+// FileUpload or StreamingDataCaptureUpload stream.
+const DefaultUploadChunkSize = 64 * 1024
+
+// ErrInvalidUploadChunkSize indicates that the upload chunk size is not greater than 0.
+var ErrInvalidUploadChunkSize = errors.New("upload chunk size must be greater than 0")
 
 // Default time to wait in milliseconds to check if a file has been modified.
 var fileLastModifiedMillis = 10000
@@ -26,7 +38,45 @@ func SetFileLastModifiedMillis(lastModifiedMillis int) {
 
 var clock = clk.New()
 
-func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f *os.File, partID string, tags []string) error {
+// FileUploadChecksumKey is the key of the MethodParameters of the UploadMetadata of an arbitrary file
+// upload, whose value is the hex encoded SHA-256 checksum of the uploaded contents, so the server can
+// verify the upload is complete & uncorrupted.
+const FileUploadChecksumKey = "sha256"
+
+// gzipFileExtension is appended to the file extension of arbitrary files which are compressed before
+// upload, so the backend knows to decompress them.
+const gzipFileExtension = ".gz"
+
+// compressedFileExtensions are the extensions of arbitrary files which are already compressed, and so
+// are uploaded as is even if compression is enabled.
+var compressedFileExtensions = map[string]bool{
+	".gz":   true,
+	".zip":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+	".mp4":  true,
+	".mov":  true,
+	".mkv":  true,
+	".mp3":  true,
+}
+
+func shouldCompress(fileExtension string) bool {
+	return !compressedFileExtensions[strings.ToLower(fileExtension)]
+}
+
+func uploadArbitraryFile(
+	ctx context.Context,
+	client v1.DataSyncServiceClient,
+	f *os.File,
+	partID string,
+	tags []string,
+	chunkSize int,
+	compress bool,
+	limiter *rate.Limiter,
+) error {
 	stream, err := client.FileUpload(ctx)
 	if err != nil {
 		return err
@@ -49,12 +99,23 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 		return errors.New("file modified too recently")
 	}
 
+	fileExtension := filepath.Ext(f.Name())
+	compress = compress && shouldCompress(fileExtension)
+	if compress {
+		fileExtension += gzipFileExtension
+	}
+	// the checksum is sent in the metadata, ahead of the contents, so the file is read twice
+	checksumParams, err := uploadChecksumParams(f, compress)
+	if err != nil {
+		return errors.Wrapf(err, "error computing checksum of %s", f.Name())
+	}
 	md := &v1.UploadMetadata{
-		PartId:        partID,
-		Type:          v1.DataType_DATA_TYPE_FILE,
-		FileName:      path,
-		FileExtension: filepath.Ext(f.Name()),
-		Tags:          tags,
+		PartId:           partID,
+		Type:             v1.DataType_DATA_TYPE_FILE,
+		FileName:         path,
+		FileExtension:    fileExtension,
+		Tags:             tags,
+		MethodParameters: checksumParams,
 	}
 
 	// Send metadata FileUploadRequest.
@@ -67,7 +128,13 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 		return err
 	}
 
-	if err := sendFileUploadRequests(ctx, stream, f); err != nil {
+	var contents io.Reader = f
+	if compress {
+		gzipContents := newGzipReader(f)
+		defer gzipContents.Close()
+		contents = gzipContents
+	}
+	if err := sendFileUploadRequests(ctx, stream, contents, chunkSize, limiter); err != nil {
 		return errors.Wrapf(err, "error syncing %s", f.Name())
 	}
 
@@ -78,7 +145,47 @@ func uploadArbitraryFile(ctx context.Context, client v1.DataSyncServiceClient, f
 	return nil
 }
 
-func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileUploadClient, f *os.File) error {
+// uploadChecksumParams returns the MethodParameters holding the checksum of the contents of f which are
+// uploaded, compressed if compress is set, and seeks f back to its start.
+func uploadChecksumParams(f *os.File, compress bool) (map[string]*anypb.Any, error) {
+	var contents io.Reader = f
+	if compress {
+		gzipContents := newGzipReader(f)
+		defer gzipContents.Close()
+		contents = gzipContents
+	}
+	checksum := sha256.New()
+	if _, err := io.Copy(checksum, contents); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	checksumParam, err := anypb.New(wrapperspb.String(hex.EncodeToString(checksum.Sum(nil))))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*anypb.Any{FileUploadChecksumKey: checksumParam}, nil
+}
+
+// newGzipReader returns a reader of the gzip compressed contents of r, which are compressed as they are read.
+// The reader must be closed to release the compressing goroutine if it is not read to the end.
+func newGzipReader(r io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	goutils.PanicCapturingGo(func() {
+		gw := gzip.NewWriter(pw)
+		_, err := io.Copy(gw, r)
+		if err == nil {
+			err = gw.Close()
+		}
+		pw.CloseWithError(err)
+	})
+	return pr
+}
+
+func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileUploadClient, r io.Reader, chunkSize int,
+	limiter *rate.Limiter,
+) error {
 	// Loop until there is no more content to be read from file.
 	for {
 		select {
@@ -86,7 +193,7 @@ func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileU
 			return context.Canceled
 		default:
 			// Get the next UploadRequest from the file.
-			uploadReq, err := getNextFileUploadRequest(ctx, f)
+			uploadReq, err := getNextFileUploadRequest(ctx, r, chunkSize)
 
 			// EOF means we've completed successfully.
 			if errors.Is(err, io.EOF) {
@@ -97,6 +204,9 @@ func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileU
 				return err
 			}
 
+			if err := waitToUpload(ctx, limiter, len(uploadReq.GetFileContents().GetData())); err != nil {
+				return err
+			}
 			if err = stream.Send(uploadReq); err != nil {
 				return err
 			}
@@ -104,13 +214,13 @@ func sendFileUploadRequests(ctx context.Context, stream v1.DataSyncService_FileU
 	}
 }
 
-func getNextFileUploadRequest(ctx context.Context, f *os.File) (*v1.FileUploadRequest, error) {
+func getNextFileUploadRequest(ctx context.Context, r io.Reader, chunkSize int) (*v1.FileUploadRequest, error) {
 	select {
 	case <-ctx.Done():
 		return nil, context.Canceled
 	default:
 		// Get the next file data reading from file, check for an error.
-		next, err := readNextFileChunk(f)
+		next, err := readNextFileChunk(r, chunkSize)
 		if err != nil {
 			return nil, err
 		}
@@ -123,14 +233,16 @@ func getNextFileUploadRequest(ctx context.Context, f *os.File) (*v1.FileUploadRe
 	}
 }
 
-func readNextFileChunk(f *os.File) (*v1.FileData, error) {
-	byteArr := make([]byte, UploadChunkSize)
-	numBytesRead, err := f.Read(byteArr)
-	if numBytesRead < UploadChunkSize {
-		byteArr = byteArr[:numBytesRead]
+func readNextFileChunk(r io.Reader, chunkSize int) (*v1.FileData, error) {
+	byteArr := make([]byte, chunkSize)
+	// fill the chunk, as readers such as the gzip reader can return less than is left to be read
+	numBytesRead, err := io.ReadFull(r, byteArr)
+	// the last chunk is allowed to be smaller
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &v1.FileData{Data: byteArr}, nil
+	return &v1.FileData{Data: byteArr[:numBytesRead]}, nil
 }
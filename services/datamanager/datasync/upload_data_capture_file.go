@@ -8,6 +8,8 @@ import (
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
 	pb "go.viam.com/api/component/camera/v1"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.viam.com/rdk/services/datamanager/datacapture"
@@ -17,7 +19,20 @@ import (
 // StreamingDataCaptureUpload.
 var MaxUnaryFileSize = int64(units.MB)
 
-func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient, f *datacapture.File, partID string) error {
+// uploadFunc uploads the sensorData of a file described by uploadMD.
+type uploadFunc func(uploadMD *v1.UploadMetadata, sensorData []*v1.SensorData, fileSize int64) error
+
+// uploadDataCaptureFile uploads the readings in f. A binary file may take several uploads, so uploaded
+// records how many of them have succeeded, and those are skipped when the upload of f is retried.
+func uploadDataCaptureFile(
+	ctx context.Context,
+	client v1.DataSyncServiceClient,
+	f *datacapture.File,
+	partID string,
+	chunkSize int,
+	limiter *rate.Limiter,
+	uploaded *int,
+) error {
 	md := f.ReadMetadata()
 	sensorData, err := datacapture.SensorDataFromFile(f)
 	if err != nil {
@@ -29,13 +44,57 @@ func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient,
 		return nil
 	}
 
-	if md.GetType() == v1.DataType_DATA_TYPE_BINARY_SENSOR && len(sensorData) > 1 {
-		return errors.New("binary sensor data file with more than one sensor reading is not supported")
+	if md.GetType() == v1.DataType_DATA_TYPE_BINARY_SENSOR {
+		skip := *uploaded
+		upload := func(uploadMD *v1.UploadMetadata, sensorData []*v1.SensorData, fileSize int64) error {
+			if skip > 0 {
+				skip--
+				return nil
+			}
+			if err := uploadSensorData(ctx, client, uploadMD, sensorData, fileSize, chunkSize, limiter); err != nil {
+				return err
+			}
+			*uploaded++
+			return nil
+		}
+		// A binary file may batch several readings, each of which is uploaded individually.
+		for _, sd := range sensorData {
+			// The size of a batched file isn't representative of the size of each of its readings.
+			readingSize := f.Size()
+			if len(sensorData) > 1 {
+				readingSize = int64(proto.Size(sd))
+			}
+			if err := uploadBinarySensorData(upload, md, sd, partID, readingSize); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	if md.GetType() == v1.DataType_DATA_TYPE_BINARY_SENSOR && md.GetMethodName() == datacapture.GetImages {
+	// Build UploadMetadata
+	uploadMD := &v1.UploadMetadata{
+		PartId:           partID,
+		ComponentType:    md.GetComponentType(),
+		ComponentName:    md.GetComponentName(),
+		MethodName:       md.GetMethodName(),
+		Type:             md.GetType(),
+		MethodParameters: md.GetMethodParameters(),
+		FileExtension:    md.GetFileExtension(),
+		Tags:             md.GetTags(),
+	}
+	return uploadSensorData(ctx, client, uploadMD, sensorData, f.Size(), chunkSize, limiter)
+}
+
+func uploadBinarySensorData(
+	upload uploadFunc,
+	md *v1.DataCaptureMetadata,
+	sd *v1.SensorData,
+	partID string,
+	readingSize int64,
+) error {
+	if md.GetMethodName() == datacapture.GetImages {
 		var res pb.GetImagesResponse
-		if err := mapstructure.Decode(sensorData[0].GetStruct().AsMap(), &res); err != nil {
+		if err := mapstructure.Decode(sd.GetStruct().AsMap(), &res); err != nil {
 			return err
 		}
 
@@ -47,7 +106,7 @@ func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient,
 		if timeCaptured != nil {
 			timeRequested, timeReceived = timeCaptured, timeCaptured
 		} else {
-			sensorMD := sensorData[0].GetMetadata()
+			sensorMD := sd.GetMetadata()
 			timeRequested = sensorMD.GetTimeRequested()
 			timeReceived = sensorMD.GetTimeReceived()
 		}
@@ -74,29 +133,28 @@ func uploadDataCaptureFile(ctx context.Context, client v1.DataSyncServiceClient,
 				FileExtension:    getFileExtFromImageFormat(img.GetFormat()),
 				Tags:             md.GetTags(),
 			}
-			if err := uploadSensorData(ctx, client, newUploadMD, newSensorData, f.Size()); err != nil {
+			if err := upload(newUploadMD, newSensorData, readingSize); err != nil {
 				return err
 			}
 		}
-	} else {
-		// Build UploadMetadata
-		uploadMD := &v1.UploadMetadata{
-			PartId:           partID,
-			ComponentType:    md.GetComponentType(),
-			ComponentName:    md.GetComponentName(),
-			MethodName:       md.GetMethodName(),
-			Type:             md.GetType(),
-			MethodParameters: md.GetMethodParameters(),
-			FileExtension:    md.GetFileExtension(),
-			Tags:             md.GetTags(),
-		}
-		return uploadSensorData(ctx, client, uploadMD, sensorData, f.Size())
+		return nil
 	}
-	return nil
+
+	uploadMD := &v1.UploadMetadata{
+		PartId:           partID,
+		ComponentType:    md.GetComponentType(),
+		ComponentName:    md.GetComponentName(),
+		MethodName:       md.GetMethodName(),
+		Type:             md.GetType(),
+		MethodParameters: md.GetMethodParameters(),
+		FileExtension:    md.GetFileExtension(),
+		Tags:             md.GetTags(),
+	}
+	return upload(uploadMD, []*v1.SensorData{sd}, readingSize)
 }
 
 func uploadSensorData(ctx context.Context, client v1.DataSyncServiceClient, uploadMD *v1.UploadMetadata,
-	sensorData []*v1.SensorData, fileSize int64,
+	sensorData []*v1.SensorData, fileSize int64, chunkSize int, limiter *rate.Limiter,
 ) error {
 	// If it's a large binary file, we need to upload it in chunks.
 	if uploadMD.GetType() == v1.DataType_DATA_TYPE_BINARY_SENSOR && fileSize > MaxUnaryFileSize {
@@ -119,7 +177,7 @@ func uploadSensorData(ctx context.Context, client v1.DataSyncServiceClient, uplo
 		}
 
 		// Then call the function to send the rest.
-		if err := sendStreamingDCRequests(ctx, c, toUpload.GetBinary()); err != nil {
+		if err := sendStreamingDCRequests(ctx, c, toUpload.GetBinary(), chunkSize, limiter); err != nil {
 			return errors.Wrap(err, "error sending streaming data capture requests")
 		}
 
@@ -131,6 +189,9 @@ func uploadSensorData(ctx context.Context, client v1.DataSyncServiceClient, uplo
 			Metadata:       uploadMD,
 			SensorContents: sensorData,
 		}
+		if err := waitToUpload(ctx, limiter, proto.Size(ur)); err != nil {
+			return err
+		}
 		if _, err := client.DataCaptureUpload(ctx, ur); err != nil {
 			return err
 		}
@@ -141,15 +202,17 @@ func uploadSensorData(ctx context.Context, client v1.DataSyncServiceClient, uplo
 
 func sendStreamingDCRequests(ctx context.Context, stream v1.DataSyncService_StreamingDataCaptureUploadClient,
 	contents []byte,
+	chunkSize int,
+	limiter *rate.Limiter,
 ) error {
 	// Loop until there is no more content to send.
-	for i := 0; i < len(contents); i += UploadChunkSize {
+	for i := 0; i < len(contents); i += chunkSize {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 			// Get the next chunk from contents.
-			end := i + UploadChunkSize
+			end := i + chunkSize
 			if end > len(contents) {
 				end = len(contents)
 			}
@@ -163,6 +226,9 @@ func sendStreamingDCRequests(ctx context.Context, stream v1.DataSyncService_Stre
 			}
 
 			// Send request
+			if err := waitToUpload(ctx, limiter, len(chunk)); err != nil {
+				return err
+			}
 			if err := stream.Send(uploadReq); err != nil {
 				return err
 			}
@@ -0,0 +1,104 @@
+package datasync
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+func TestUploadBatchedBinaryFileRetry(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1", Type: v1.DataType_DATA_TYPE_BINARY_SENSOR}
+	buf := datacapture.NewBuffer(dir, md, datacapture.WithBinaryBatching())
+	numReadings := 3
+	for i := 0; i < numReadings; i++ {
+		test.That(t, buf.Write(&v1.SensorData{
+			Metadata: &v1.SensorMetadata{},
+			Data:     &v1.SensorData_Binary{Binary: []byte{byte(i)}},
+		}), test.ShouldBeNil)
+	}
+	test.That(t, buf.Flush(), test.ShouldBeNil)
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+datacapture.FileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, paths, test.ShouldHaveLength, 1)
+	//nolint:gosec
+	osFile, err := os.Open(paths[0])
+	test.That(t, err, test.ShouldBeNil)
+	f, err := datacapture.ReadFile(osFile)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+
+	// the second reading fails to upload the first time it is attempted
+	client := &fakeDataSyncServiceClient{failAt: 1}
+	var uploaded int
+	err = uploadDataCaptureFile(context.Background(), client, f, "part", 1024, nil, &uploaded)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, uploaded, test.ShouldEqual, 1)
+
+	// retrying uploads the remaining readings without uploading the first again
+	test.That(t, uploadDataCaptureFile(context.Background(), client, f, "part", 1024, nil, &uploaded), test.ShouldBeNil)
+	test.That(t, uploaded, test.ShouldEqual, numReadings)
+	test.That(t, client.uploaded, test.ShouldResemble, [][]byte{{0}, {1}, {2}})
+}
+
+func TestUnaryUploadIsLimited(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{ComponentName: "cam1", Type: v1.DataType_DATA_TYPE_BINARY_SENSOR}
+	buf := datacapture.NewBuffer(dir, md)
+	test.That(t, buf.Write(&v1.SensorData{
+		Metadata: &v1.SensorMetadata{},
+		Data:     &v1.SensorData_Binary{Binary: make([]byte, 100)},
+	}), test.ShouldBeNil)
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+datacapture.FileExt))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, paths, test.ShouldHaveLength, 1)
+	//nolint:gosec
+	osFile, err := os.Open(paths[0])
+	test.That(t, err, test.ShouldBeNil)
+	f, err := datacapture.ReadFile(osFile)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+
+	// the limiter's burst is smaller than the request, so it is waited for in pieces
+	limiter := rate.NewLimiter(rate.Limit(1000), 50)
+	var uploaded int
+	client := &fakeDataSyncServiceClient{failAt: -1}
+	start := time.Now()
+	test.That(t, uploadDataCaptureFile(context.Background(), client, f, "part", 1024, limiter, &uploaded), test.ShouldBeNil)
+	test.That(t, client.uploaded, test.ShouldHaveLength, 1)
+	// the request is over 100 bytes, so uploading it waited for at least the 50 past the burst
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 50*time.Millisecond)
+}
+
+// fakeDataSyncServiceClient records the binary readings uploaded to it, failing the upload with index failAt.
+type fakeDataSyncServiceClient struct {
+	v1.DataSyncServiceClient
+	failAt   int
+	calls    int
+	uploaded [][]byte
+}
+
+func (c *fakeDataSyncServiceClient) DataCaptureUpload(
+	ctx context.Context,
+	ur *v1.DataCaptureUploadRequest,
+	opts ...grpc.CallOption,
+) (*v1.DataCaptureUploadResponse, error) {
+	c.calls++
+	if c.calls-1 == c.failAt {
+		return nil, errors.New("oh no error")
+	}
+	for _, sd := range ur.GetSensorContents() {
+		c.uploaded = append(c.uploaded, sd.GetBinary())
+	}
+	return &v1.DataCaptureUploadResponse{}, nil
+}
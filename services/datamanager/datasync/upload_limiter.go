@@ -0,0 +1,37 @@
+package datasync
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// ErrInvalidMaxUploadBytesPerSec indicates that the max upload bytes per second is negative.
+var ErrInvalidMaxUploadBytesPerSec = errors.New("max upload bytes per second can't be negative")
+
+// newUploadLimiter returns a limiter of the upload throughput to maxUploadBytesPerSec, or nil if it is
+// 0, meaning unlimited. Its burst is a single chunk, as chunks are the unit uploads are limited by.
+func newUploadLimiter(maxUploadBytesPerSec, chunkSize int) *rate.Limiter {
+	if maxUploadBytesPerSec == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(maxUploadBytesPerSec), chunkSize)
+}
+
+// waitToUpload blocks until numBytes can be uploaded without exceeding the limiter's throughput.
+// Uploads larger than the limiter's burst, such as unary uploads of whole files, wait for it in
+// burst sized pieces. A nil limiter never blocks.
+func waitToUpload(ctx context.Context, limiter *rate.Limiter, numBytes int) error {
+	if limiter == nil {
+		return nil
+	}
+	for numBytes > 0 {
+		n := min(numBytes, limiter.Burst())
+		if err := limiter.WaitN(ctx, n); err != nil {
+			return err
+		}
+		numBytes -= n
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package builtin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
@@ -28,8 +30,8 @@ import (
 )
 
 var (
-	stateTTL              = time.Hour * 24
-	stateTTLCheckInterval = time.Minute
+	defaultStateTTL              = time.Hour * 24
+	defaultStateTTLCheckInterval = time.Minute
 )
 
 func init() {
@@ -71,16 +73,48 @@ type inputEnabledActuator interface {
 // ErrNotImplemented is thrown when an unreleased function is called.
 var ErrNotImplemented = errors.New("function coming soon but not yet implemented")
 
-// Config describes how to configure the service; currently only used for specifying dependency on framesystem service.
+// Config describes how to configure the service.
+// TTLSec is how long the history of terminated executions is kept, defaults to 24 hours.
+// TTLCheckIntervalSec is how often executions older than the TTL are removed, defaults
+// to 1 minute, or the TTL if it is shorter.
+// RequireMovementSensorOffset makes MoveOnGlobe fail if the offset between the movement sensor
+// & the base can't be found in the frame system, rather than assuming they are coincident.
 type Config struct {
-	LogFilePath string `json:"log_file_path"`
+	LogFilePath                 string  `json:"log_file_path"`
+	TTLSec                      float64 `json:"ttl_sec,omitempty"`
+	TTLCheckIntervalSec         float64 `json:"ttl_check_interval_sec,omitempty"`
+	RequireMovementSensorOffset bool    `json:"require_movement_sensor_offset,omitempty"`
 }
 
 // Validate here adds a dependency on the internal framesystem service.
 func (c *Config) Validate(path string) ([]string, error) {
+	if c.TTLSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("ttl_sec can't be negative"))
+	}
+	if c.TTLCheckIntervalSec < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("ttl_check_interval_sec can't be negative"))
+	}
+	if ttl, ttlCheckInterval := c.stateTTL(); ttl < ttlCheckInterval {
+		return nil, resource.NewConfigValidationError(path, errors.New("ttl_sec can't be lower than ttl_check_interval_sec"))
+	}
 	return []string{framesystem.InternalServiceName.String()}, nil
 }
 
+// stateTTL returns the TTL & TTL check interval of the motion state, applying defaults for unset values.
+func (c *Config) stateTTL() (time.Duration, time.Duration) {
+	ttl := defaultStateTTL
+	if c.TTLSec > 0 {
+		ttl = time.Duration(c.TTLSec * float64(time.Second))
+	}
+	ttlCheckInterval := defaultStateTTLCheckInterval
+	if c.TTLCheckIntervalSec > 0 {
+		ttlCheckInterval = time.Duration(c.TTLCheckIntervalSec * float64(time.Second))
+	} else if ttl < ttlCheckInterval {
+		ttlCheckInterval = ttl
+	}
+	return ttl, ttlCheckInterval
+}
+
 // NewBuiltIn returns a new move and grab service for the given robot.
 func NewBuiltIn(
 	ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
@@ -138,11 +172,17 @@ func (ms *builtIn) Reconfigure(
 	ms.slamServices = slamServices
 	ms.visionServices = visionServices
 	ms.components = components
+	ms.requireMovementSensorOffset = config.RequireMovementSensorOffset
+	// the frame system may have changed, so the offsets of movement sensors must be recomputed
+	ms.movementSensorOffsetsMu.Lock()
+	ms.movementSensorOffsets = nil
+	ms.movementSensorOffsetsMu.Unlock()
 	if ms.state != nil {
 		ms.state.Stop()
 	}
 
-	state, err := state.NewState(stateTTL, stateTTLCheckInterval, ms.logger)
+	ttl, ttlCheckInterval := config.stateTTL()
+	state, err := state.NewState(ttl, ttlCheckInterval, ms.logger)
 	if err != nil {
 		return err
 	}
@@ -160,6 +200,17 @@ type builtIn struct {
 	components      map[resource.Name]resource.Resource
 	logger          logging.Logger
 	state           *state.State
+
+	requireMovementSensorOffset bool
+	// movementSensorOffsetsMu protects movementSensorOffsets, which is used by executions
+	// replanning without holding mu
+	movementSensorOffsetsMu sync.Mutex
+	movementSensorOffsets   map[movementSensorOffsetKey]*referenceframe.PoseInFrame
+
+	// virtualObstaclesMu protects virtualObstacles, which are added through DoCommand to simulate
+	// obstacles being detected during MoveOnGlobe executions
+	virtualObstaclesMu sync.Mutex
+	virtualObstacles   []*spatialmath.GeoGeometry
 }
 
 func (ms *builtIn) Close(ctx context.Context) error {
@@ -171,6 +222,69 @@ func (ms *builtIn) Close(ctx context.Context) error {
 	return nil
 }
 
+// DoCommand supports simulating obstacle detections for testing navigation.
+// The "add_virtual_obstacles" command takes a list of GeoGeometries, in the format used to configure
+// them, in its "obstacles" field. While MoveOnGlobe executes, virtual obstacles are checked at the
+// obstacle polling frequency & trigger a replan if they intersect the plan, as detected obstacles do.
+// The "clear_virtual_obstacles" command removes all virtual obstacles.
+func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd["command"]
+	if !ok {
+		return nil, errors.New("missing 'command' value")
+	}
+	switch name {
+	case "add_virtual_obstacles":
+		obstacles, err := virtualObstaclesFromCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+		ms.virtualObstaclesMu.Lock()
+		defer ms.virtualObstaclesMu.Unlock()
+		ms.virtualObstacles = append(ms.virtualObstacles, obstacles...)
+		return map[string]interface{}{"virtual_obstacles": len(ms.virtualObstacles)}, nil
+	case "clear_virtual_obstacles":
+		ms.virtualObstaclesMu.Lock()
+		defer ms.virtualObstaclesMu.Unlock()
+		ms.virtualObstacles = nil
+		return map[string]interface{}{"virtual_obstacles": 0}, nil
+	default:
+		return nil, fmt.Errorf("no such command: %s", name)
+	}
+}
+
+// virtualObstaclesFromCommand parses & validates the obstacles of an "add_virtual_obstacles" command.
+func virtualObstaclesFromCommand(cmd map[string]interface{}) ([]*spatialmath.GeoGeometry, error) {
+	rawObstacles, ok := cmd["obstacles"]
+	if !ok {
+		return nil, errors.New("missing 'obstacles' value")
+	}
+	// the obstacles are decoded as generic JSON, so they are round tripped to be parsed as configs
+	data, err := json.Marshal(rawObstacles)
+	if err != nil {
+		return nil, err
+	}
+	var configs []*spatialmath.GeoGeometryConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, errors.Wrap(err, "could not interpret obstacles as a list of geo geometries")
+	}
+	for i, config := range configs {
+		if config == nil || config.Location == nil {
+			return nil, fmt.Errorf("virtual obstacle %d has no location", i)
+		}
+		if len(config.Geometries) == 0 {
+			return nil, fmt.Errorf("virtual obstacle %d has no geometries", i)
+		}
+	}
+	return spatialmath.GeoGeometriesFromConfigs(configs)
+}
+
+// currentVirtualObstacles returns the virtual obstacles added through DoCommand.
+func (ms *builtIn) currentVirtualObstacles() []*spatialmath.GeoGeometry {
+	ms.virtualObstaclesMu.Lock()
+	defer ms.virtualObstaclesMu.Unlock()
+	return append([]*spatialmath.GeoGeometry{}, ms.virtualObstacles...)
+}
+
 // Move takes a goal location and will plan and execute a movement to move a component specified by its name to that destination.
 func (ms *builtIn) Move(
 	ctx context.Context,
@@ -259,22 +373,122 @@ func (ms *builtIn) MoveOnMap(ctx context.Context, req motion.MoveOnMapReq) (moti
 	defer ms.mu.RUnlock()
 	ms.logger.CDebugf(ctx, "MoveOnMap called with %s", req)
 
-	// TODO: Deprecated: remove once no motion apis use the opid system
-	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+	rejectIfMoving, err := rejectIfMovingFromExtra(req.Extra)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if rejectIfMoving {
+		if err := ms.validateNotMoving(ctx, req.ComponentName); err != nil {
+			return uuid.Nil, err
+		}
+	} else {
+		// TODO: Deprecated: remove once no motion apis use the opid system
+		operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+	}
+
+	label, err := labelFromExtra(req.Extra)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	minReplanInterval, err := minReplanIntervalFromExtra(req.Extra)
+	if err != nil {
+		return uuid.Nil, err
+	}
 
-	id, err := state.StartExecution(ctx, ms.state, req.ComponentName, req, ms.newMoveOnMapRequest)
+	id, err := state.StartExecution(ctx, ms.state, req.ComponentName, req, ms.newMoveOnMapRequest,
+		state.WithLabel(label), state.WithMinReplanInterval(minReplanInterval))
 	if err != nil {
+		// a concurrent move for the component may have started since validateNotMoving
+		if rejectIfMoving && errors.Is(err, state.ErrActiveExecution) {
+			return uuid.Nil, fmt.Errorf("%w: %w", motion.ErrBaseAlreadyMoving, err)
+		}
 		return uuid.Nil, err
 	}
 
 	return id, nil
 }
 
+// labelFromExtra returns the optional label of an execution passed through extra.
+func labelFromExtra(extra map[string]interface{}) (string, error) {
+	labelRaw, ok := extra[motion.LabelExtraKey]
+	if !ok {
+		return "", nil
+	}
+	label, ok := labelRaw.(string)
+	if !ok {
+		return "", fmt.Errorf("could not interpret %s field as string", motion.LabelExtraKey)
+	}
+	return label, nil
+}
+
+// rejectIfMovingFromExtra returns whether a move should be rejected if its component is already moving.
+func rejectIfMovingFromExtra(extra map[string]interface{}) (bool, error) {
+	rejectRaw, ok := extra[motion.RejectIfMovingExtraKey]
+	if !ok {
+		return false, nil
+	}
+	reject, ok := rejectRaw.(bool)
+	if !ok {
+		return false, fmt.Errorf("could not interpret %s field as bool", motion.RejectIfMovingExtraKey)
+	}
+	return reject, nil
+}
+
+// validateNotMoving returns an error wrapping motion.ErrBaseAlreadyMoving if the component has an
+// active execution or reports that it is moving, so that a move which sets
+// motion.RejectIfMovingExtraKey is rejected before it plans.
+func (ms *builtIn) validateNotMoving(ctx context.Context, componentName resource.Name) error {
+	if err := ms.state.ValidateNoActiveExecutionID(componentName); err != nil {
+		return fmt.Errorf("%w: %w", motion.ErrBaseAlreadyMoving, err)
+	}
+	actuator, ok := ms.components[componentName].(resource.Actuator)
+	if !ok {
+		return nil
+	}
+	moving, err := actuator.IsMoving(ctx)
+	if err != nil {
+		return err
+	}
+	if moving {
+		return fmt.Errorf("%w: %s", motion.ErrBaseAlreadyMoving, componentName)
+	}
+	return nil
+}
+
+// minReplanIntervalFromExtra returns the optional minimum time between replans of an execution passed through extra.
+func minReplanIntervalFromExtra(extra map[string]interface{}) (time.Duration, error) {
+	intervalRaw, ok := extra[motion.MinReplanIntervalExtraKey]
+	if !ok {
+		return 0, nil
+	}
+	intervalSec, ok := intervalRaw.(float64)
+	if !ok {
+		return 0, fmt.Errorf("could not interpret %s field as float", motion.MinReplanIntervalExtraKey)
+	}
+	if err := validateNotNegNorNaN(intervalSec, motion.MinReplanIntervalExtraKey); err != nil {
+		return 0, err
+	}
+	return time.Duration(intervalSec * float64(time.Second)), nil
+}
+
 type validatedExtra struct {
 	maxReplans       int
 	replanCostFactor float64
+	seedReplans      bool
 	motionProfile    string
-	extra            map[string]interface{}
+	// arrivalRadiusMM is how close a MoveOnMap must get to its goal to have arrived, zero if unset
+	arrivalRadiusMM float64
+	// arrivalCheckFreqHz is how often arrival at the goal is checked while executing a plan, zero if
+	// it is only checked once the plan has been fully executed
+	arrivalCheckFreqHz float64
+	// stepTimeoutSec is the longest the base may take to advance from one step of the plan to the next
+	// before the execution replans, zero if unbounded. Only enforced for bases which report their step.
+	stepTimeoutSec float64
+	// staleSLAMPositionTimeoutSec is how long MoveOnMap's slam service may report an unchanged position
+	// while the base is moving before it is considered stale, zero if it never is
+	staleSLAMPositionTimeoutSec float64
+	extra                       map[string]interface{}
 }
 
 func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
@@ -304,19 +518,173 @@ func newValidatedExtra(extra map[string]interface{}) (validatedExtra, error) {
 		}
 		replanCostFactor = costFactor
 	}
+	seedReplans := false
+	if seedReplansRaw, ok := extra["seed_replans"]; ok {
+		if seedReplans, ok = seedReplansRaw.(bool); !ok {
+			return validatedExtra{}, errors.New("could not interpret seed_replans field as bool")
+		}
+	}
+	var arrivalRadiusMM float64
+	if arrivalRadiusRaw, ok := extra["arrival_radius_mm"]; ok {
+		if arrivalRadiusMM, ok = arrivalRadiusRaw.(float64); !ok {
+			return validatedExtra{}, errors.New("could not interpret arrival_radius_mm field as float")
+		}
+		if math.IsNaN(arrivalRadiusMM) || arrivalRadiusMM <= 0 {
+			return validatedExtra{}, errors.New("arrival_radius_mm must be positive")
+		}
+	}
+	var arrivalCheckFreqHz float64
+	if arrivalCheckFreqRaw, ok := extra["arrival_check_freq_hz"]; ok {
+		if arrivalCheckFreqHz, ok = arrivalCheckFreqRaw.(float64); !ok {
+			return validatedExtra{}, errors.New("could not interpret arrival_check_freq_hz field as float")
+		}
+		if err := validateNotNegNorNaN(arrivalCheckFreqHz, "arrival_check_freq_hz"); err != nil {
+			return validatedExtra{}, err
+		}
+	}
+	var stepTimeoutSec float64
+	if stepTimeoutRaw, ok := extra["step_timeout_sec"]; ok {
+		if stepTimeoutSec, ok = stepTimeoutRaw.(float64); !ok {
+			return validatedExtra{}, errors.New("could not interpret step_timeout_sec field as float")
+		}
+		if err := validateNotNegNorNaN(stepTimeoutSec, "step_timeout_sec"); err != nil {
+			return validatedExtra{}, err
+		}
+	}
+	var staleSLAMPositionTimeoutSec float64
+	if staleTimeoutRaw, ok := extra[motion.StaleSLAMPositionTimeoutExtraKey]; ok {
+		if staleSLAMPositionTimeoutSec, ok = staleTimeoutRaw.(float64); !ok {
+			return validatedExtra{}, errors.Errorf("could not interpret %s field as float", motion.StaleSLAMPositionTimeoutExtraKey)
+		}
+		if err := validateNotNegNorNaN(staleSLAMPositionTimeoutSec, motion.StaleSLAMPositionTimeoutExtraKey); err != nil {
+			return validatedExtra{}, err
+		}
+	}
+
+	planningOpts, err := newPlanningOptions(extra)
+	if err != nil {
+		return validatedExtra{}, err
+	}
+	planningOpts.applyTo(extra)
 
 	if _, ok := extra["smooth_iter"]; !ok {
 		extra["smooth_iter"] = defaultSmoothIter
 	}
 
 	return validatedExtra{
-		maxReplans:       maxReplans,
-		motionProfile:    motionProfile,
-		replanCostFactor: replanCostFactor,
-		extra:            extra,
+		maxReplans:                  maxReplans,
+		motionProfile:               motionProfile,
+		replanCostFactor:            replanCostFactor,
+		seedReplans:                 seedReplans,
+		arrivalRadiusMM:             arrivalRadiusMM,
+		arrivalCheckFreqHz:          arrivalCheckFreqHz,
+		stepTimeoutSec:              stepTimeoutSec,
+		staleSLAMPositionTimeoutSec: staleSLAMPositionTimeoutSec,
+		extra:                       extra,
 	}, nil
 }
 
+// planningOptions are the motion planner options which can be set through the extra of MoveOnGlobe
+// and MoveOnMap. All of them are optional:
+//   - "planning_alg": the planning algorithm, one of "cbirrt" or "rrtstar". Not supported by PTG bases.
+//   - "timeout": seconds after which planning is abandoned.
+//   - "smooth_iter": number of path smoothing iterations, defaults to 30.
+//   - "resolution": the resolution, in mm or degrees, at which the path is checked for collisions.
+//   - "collision_buffer_mm": distance to keep between the component and obstacles.
+//   - "max_ik_solutions": number of IK solutions to find before planning.
+//   - "num_threads": number of threads used by the IK solver.
+//   - "rseed": the random seed of the planner, for reproducible plans.
+//
+// Other keys of extra are passed through to the planner unvalidated.
+type planningOptions struct {
+	PlanningAlg       *string  `json:"planning_alg"`
+	Timeout           *float64 `json:"timeout"`
+	SmoothIter        *int     `json:"smooth_iter"`
+	Resolution        *float64 `json:"resolution"`
+	CollisionBufferMM *float64 `json:"collision_buffer_mm"`
+	MaxIKSolutions    *int     `json:"max_ik_solutions"`
+	NumThreads        *int     `json:"num_threads"`
+	RandomSeed        *int     `json:"rseed"`
+}
+
+var planningOptionKeys = []string{
+	"planning_alg", "timeout", "smooth_iter", "resolution", "collision_buffer_mm", "max_ik_solutions", "num_threads", "rseed",
+}
+
+func newPlanningOptions(extra map[string]interface{}) (planningOptions, error) {
+	var opts planningOptions
+	known := map[string]interface{}{}
+	for _, key := range planningOptionKeys {
+		if v, ok := extra[key]; ok {
+			known[key] = v
+		}
+	}
+	raw, err := json.Marshal(known)
+	if err != nil {
+		return planningOptions{}, errors.Wrap(err, "invalid planning options in extra")
+	}
+	if err := json.Unmarshal(raw, &opts); err != nil {
+		return planningOptions{}, errors.Wrap(err, "invalid planning options in extra")
+	}
+
+	if opts.PlanningAlg != nil {
+		switch *opts.PlanningAlg {
+		case "", "cbirrt", "rrtstar":
+		default:
+			return planningOptions{}, fmt.Errorf("unsupported planning_alg %q", *opts.PlanningAlg)
+		}
+	}
+	for name, f := range map[string]*float64{
+		"timeout":             opts.Timeout,
+		"resolution":          opts.Resolution,
+		"collision_buffer_mm": opts.CollisionBufferMM,
+	} {
+		if f == nil {
+			continue
+		}
+		if err := validateNotNegNorNaN(*f, name); err != nil {
+			return planningOptions{}, err
+		}
+	}
+	for name, i := range map[string]*int{
+		"smooth_iter":      opts.SmoothIter,
+		"max_ik_solutions": opts.MaxIKSolutions,
+		"num_threads":      opts.NumThreads,
+	} {
+		if i != nil && *i < 0 {
+			return planningOptions{}, fmt.Errorf("%s may not be negative", name)
+		}
+	}
+	return opts, nil
+}
+
+// applyTo writes the options back into extra with the types the planner expects, so that
+// e.g. an integer timeout or a float random seed coming from a client is not silently ignored.
+func (opts planningOptions) applyTo(extra map[string]interface{}) {
+	if opts.PlanningAlg != nil {
+		extra["planning_alg"] = *opts.PlanningAlg
+	}
+	for name, f := range map[string]*float64{
+		"timeout":             opts.Timeout,
+		"resolution":          opts.Resolution,
+		"collision_buffer_mm": opts.CollisionBufferMM,
+	} {
+		if f != nil {
+			extra[name] = *f
+		}
+	}
+	for name, i := range map[string]*int{
+		"smooth_iter":      opts.SmoothIter,
+		"max_ik_solutions": opts.MaxIKSolutions,
+		"num_threads":      opts.NumThreads,
+		"rseed":            opts.RandomSeed,
+	} {
+		if i != nil {
+			extra[name] = *i
+		}
+	}
+}
+
 func (ms *builtIn) MoveOnGlobe(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
 	if err := ctx.Err(); err != nil {
 		return uuid.Nil, err
@@ -324,14 +692,39 @@ func (ms *builtIn) MoveOnGlobe(ctx context.Context, req motion.MoveOnGlobeReq) (
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 	ms.logger.CDebugf(ctx, "MoveOnGlobe called with %s", req)
-	// TODO: Deprecated: remove once no motion apis use the opid system
-	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+	rejectIfMoving, err := rejectIfMovingFromExtra(req.Extra)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if rejectIfMoving {
+		if err := ms.validateNotMoving(ctx, req.ComponentName); err != nil {
+			return uuid.Nil, err
+		}
+	} else {
+		// TODO: Deprecated: remove once no motion apis use the opid system
+		operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+	}
 
-	id, err := state.StartExecution(ctx, ms.state, req.ComponentName, req, ms.newMoveOnGlobeRequest)
+	label, err := labelFromExtra(req.Extra)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
+	minReplanInterval, err := minReplanIntervalFromExtra(req.Extra)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id, err := state.StartExecution(ctx, ms.state, req.ComponentName, req, ms.newMoveOnGlobeRequest,
+		state.WithLabel(label), state.WithMinReplanInterval(minReplanInterval))
+	if err != nil {
+		// a concurrent move for the component may have started since validateNotMoving
+		if rejectIfMoving && errors.Is(err, state.ErrActiveExecution) {
+			return uuid.Nil, fmt.Errorf("%w: %w", motion.ErrBaseAlreadyMoving, err)
+		}
+		return uuid.Nil, err
+	}
+
 	return id, nil
 }
 
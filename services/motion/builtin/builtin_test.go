@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sync"
 	"testing"
 	"time"
 
@@ -42,22 +43,22 @@ func TestMoveResponseString(t *testing.T) {
 	testCases := []testCase{
 		{
 			"when executeResponse.Replan is false & ReplanReason is empty and error is not nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: an error}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", ReplanKind:0x0}, err: an error}",
 			moveResponse{err: errors.New("an error")},
 		},
 		{
 			"when executeResponse.Replan is true & ReplanReason is not empty and error is not nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: an error}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", ReplanKind:0x0}, err: an error}",
 			moveResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}, err: errors.New("an error")},
 		},
 		{
 			"when executeResponse.Replan is true & ReplanReason is not empty and error is nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: <nil>}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", ReplanKind:0x0}, err: <nil>}",
 			moveResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}},
 		},
 		{
 			"when executeResponse.Replan is false & ReplanReason is empty and error is nil",
-			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: <nil>}",
+			"builtin.moveResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", ReplanKind:0x0}, err: <nil>}",
 			moveResponse{},
 		},
 	}
@@ -77,22 +78,22 @@ func TestReplanResponseString(t *testing.T) {
 	testCases := []testCase{
 		{
 			"when replan is true and reason is non empty and error is nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: <nil>}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", ReplanKind:0x0}, err: <nil>}",
 			replanResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}},
 		},
 		{
 			"when replan is true and reason is non empty and error is not nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\"}, err: an error}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:true, ReplanReason:\"some reason\", ReplanKind:0x0}, err: an error}",
 			replanResponse{executeResponse: state.ExecuteResponse{Replan: true, ReplanReason: "some reason"}, err: errors.New("an error")},
 		},
 		{
 			"when replan is false and error is nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: <nil>}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", ReplanKind:0x0}, err: <nil>}",
 			replanResponse{},
 		},
 		{
 			"when replan is false and error is not nil",
-			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\"}, err: an error}",
+			"builtin.replanResponse{executeResponse: state.ExecuteResponse{Replan:false, ReplanReason:\"\", ReplanKind:0x0}, err: an error}",
 			replanResponse{err: errors.New("an error")},
 		},
 	}
@@ -454,6 +455,77 @@ func TestObstacleReplanningGlobe(t *testing.T) {
 	}
 }
 
+func TestObstacleReplanningMultipleDetectors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	gpsOrigin := geo.NewPoint(0, 0)
+	dst := geo.NewPoint(gpsOrigin.Lat(), gpsOrigin.Lng()+1e-5)
+
+	injectedMovementSensor, _, kb, ms := createMoveOnGlobeEnvironment(
+		ctx,
+		t,
+		gpsOrigin,
+		spatialmath.NewPoseFromPoint(r3.Vector{X: 0, Y: 0, Z: 0}),
+		5000,
+	)
+	defer ms.Close(ctx)
+
+	// the first detector never sees anything, while the second always sees an obstacle 300mm in front of the base
+	// once the base has started moving
+	var mu sync.Mutex
+	calledCameras := []string{}
+	srvc, ok := ms.(*builtIn).visionServices[vision.Named("injectedVisionSvc")].(*inject.VisionService)
+	test.That(t, ok, test.ShouldBeTrue)
+	srvc.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calledCameras = append(calledCameras, cameraName)
+		if cameraName == "emptyCamera" || len(calledCameras) <= 2 {
+			return []*viz.Object{}, nil
+		}
+		obstaclePosition := spatialmath.NewPoseFromPoint(r3.Vector{X: 300, Y: 0, Z: 0})
+		box, err := spatialmath.NewBox(obstaclePosition, r3.Vector{X: 20, Y: 20, Z: 10}, "blocking")
+		test.That(t, err, test.ShouldBeNil)
+		detection, err := viz.NewObjectWithLabel(pointcloud.New(), "blocking-detection", box.ToProtobuf())
+		test.That(t, err, test.ShouldBeNil)
+		return []*viz.Object{detection}, nil
+	}
+
+	req := motion.MoveOnGlobeReq{
+		ComponentName:      kb.Name(),
+		Destination:        dst,
+		MovementSensorName: injectedMovementSensor.Name(),
+		MotionCfg: &motion.MotionConfiguration{
+			PositionPollingFreqHz: 1, ObstaclePollingFreqHz: 20, PlanDeviationMM: 15,
+			ObstacleDetectors: []motion.ObstacleDetectorName{
+				{VisionServiceName: vision.Named("injectedVisionSvc"), CameraName: camera.Named("emptyCamera")},
+				{VisionServiceName: vision.Named("injectedVisionSvc"), CameraName: camera.Named("injectedCamera")},
+			},
+		},
+		Extra: map[string]interface{}{"max_replans": 0, "max_ik_solutions": 1, "smooth_iter": 1},
+	}
+	executionID, err := ms.MoveOnGlobe(ctx, req)
+	test.That(t, err, test.ShouldBeNil)
+
+	timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Minute*5)
+	defer timeoutFn()
+	err = motion.PollHistoryUntilSuccessOrError(timeoutCtx, ms, time.Millisecond*5, motion.PlanHistoryReq{
+		ComponentName: req.ComponentName,
+		ExecutionID:   executionID,
+		LastPlanOnly:  true,
+	})
+
+	// the obstacle seen by the second detector triggers a replan, which fails as no replans are allowed
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, fmt.Sprintf("exceeded maximum number of replans: %d: plan failed", 0))
+
+	mu.Lock()
+	defer mu.Unlock()
+	test.That(t, len(calledCameras), test.ShouldBeGreaterThan, 2)
+	test.That(t, calledCameras[len(calledCameras)-2:], test.ShouldResemble, []string{"emptyCamera", "injectedCamera"})
+}
+
 func TestObstacleReplanningSlam(t *testing.T) {
 	cameraPoseInBase := spatialmath.NewPose(r3.Vector{0, 0, 0}, &spatialmath.OrientationVectorDegrees{OY: 1, Theta: -90})
 
@@ -1026,3 +1098,133 @@ func TestPlanHistory(t *testing.T) {
 	test.That(t, err, test.ShouldResemble, resource.NewNotFoundError(req.ComponentName))
 	test.That(t, history, test.ShouldBeNil)
 }
+
+func TestPlanningOptionsFromExtra(t *testing.T) {
+	t.Run("options are normalized to the types the planner expects", func(t *testing.T) {
+		valExtra, err := newValidatedExtra(map[string]interface{}{
+			"planning_alg": "rrtstar",
+			"timeout":      1,
+			"rseed":        7.,
+			"smooth_iter":  5.,
+			"unvalidated":  "passed through",
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.extra["planning_alg"], test.ShouldEqual, "rrtstar")
+		test.That(t, valExtra.extra["timeout"], test.ShouldEqual, 1.)
+		test.That(t, valExtra.extra["rseed"], test.ShouldEqual, 7)
+		test.That(t, valExtra.extra["smooth_iter"], test.ShouldEqual, 5)
+		test.That(t, valExtra.extra["unvalidated"], test.ShouldEqual, "passed through")
+		test.That(t, valExtra.seedReplans, test.ShouldBeFalse)
+
+		valExtra, err = newValidatedExtra(map[string]interface{}{"seed_replans": true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, valExtra.seedReplans, test.ShouldBeTrue)
+	})
+
+	t.Run("invalid options are rejected", func(t *testing.T) {
+		for _, extra := range []map[string]interface{}{
+			{"timeout": "soon"},
+			{"timeout": -1.},
+			{"timeout": math.NaN()},
+			{"planning_alg": "astar"},
+			{"planning_alg": 1},
+			{"smooth_iter": -1},
+			{"smooth_iter": 1.5},
+			{"seed_replans": "yes"},
+			{"arrival_radius_mm": "far"},
+			{"arrival_radius_mm": 0.},
+			{"arrival_radius_mm": -1.},
+			{"arrival_check_freq_hz": "often"},
+			{"arrival_check_freq_hz": -1.},
+			{"arrival_check_freq_hz": math.NaN()},
+			{"step_timeout_sec": "never"},
+			{"step_timeout_sec": -1.},
+			{motion.StaleSLAMPositionTimeoutExtraKey: "soon"},
+			{motion.StaleSLAMPositionTimeoutExtraKey: -1.},
+		} {
+			_, err := newValidatedExtra(extra)
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+
+	t.Run("a non-default option reaches the planner", func(t *testing.T) {
+		ctx := context.Background()
+		_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+		defer ms.Close(ctx)
+
+		planExecutor, err := ms.(*builtIn).newMoveOnMapRequest(ctx, motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewPoseFromPoint(r3.Vector{X: 500}),
+			SlamName:      slam.Named("test_slam"),
+			Extra:         map[string]interface{}{"timeout": 2, "collision_buffer_mm": 5},
+		}, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, mr.planRequest.Options["timeout"], test.ShouldEqual, 2.)
+		test.That(t, mr.planRequest.Options["collision_buffer_mm"], test.ShouldEqual, 5.)
+	})
+}
+
+func TestConfigStateTTL(t *testing.T) {
+	t.Run("defaults to 24 hours checked every minute", func(t *testing.T) {
+		ttl, ttlCheckInterval := (&Config{}).stateTTL()
+		test.That(t, ttl, test.ShouldEqual, time.Hour*24)
+		test.That(t, ttlCheckInterval, test.ShouldEqual, time.Minute)
+	})
+
+	t.Run("can be configured", func(t *testing.T) {
+		conf := &Config{TTLSec: 3600, TTLCheckIntervalSec: 0.5}
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		ttl, ttlCheckInterval := conf.stateTTL()
+		test.That(t, ttl, test.ShouldEqual, time.Hour)
+		test.That(t, ttlCheckInterval, test.ShouldEqual, time.Millisecond*500)
+	})
+
+	t.Run("a TTL shorter than the default check interval is checked every TTL", func(t *testing.T) {
+		conf := &Config{TTLSec: 30}
+		_, err := conf.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+		ttl, ttlCheckInterval := conf.stateTTL()
+		test.That(t, ttl, test.ShouldEqual, time.Second*30)
+		test.That(t, ttlCheckInterval, test.ShouldEqual, time.Second*30)
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		_, err := (&Config{TTLSec: -1}).Validate("path")
+		test.That(t, err, test.ShouldBeError, resource.NewConfigValidationError("path", errors.New("ttl_sec can't be negative")))
+		_, err = (&Config{TTLCheckIntervalSec: -1}).Validate("path")
+		test.That(t, err, test.ShouldBeError,
+			resource.NewConfigValidationError("path", errors.New("ttl_check_interval_sec can't be negative")))
+		_, err = (&Config{TTLSec: 1, TTLCheckIntervalSec: 2}).Validate("path")
+		test.That(t, err, test.ShouldBeError,
+			resource.NewConfigValidationError("path", errors.New("ttl_sec can't be lower than ttl_check_interval_sec")))
+	})
+
+	t.Run("is used by the motion state", func(t *testing.T) {
+		ctx := context.Background()
+		logger := logging.NewTestLogger(t)
+		conf := resource.Config{ConvertedAttributes: &Config{TTLSec: 1, TTLCheckIntervalSec: 0.1}}
+		ms, err := NewBuiltIn(ctx, resource.Dependencies{}, conf, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer ms.Close(ctx)
+		test.That(t, ms.(*builtIn).state, test.ShouldNotBeNil)
+	})
+}
+
+func TestMinReplanIntervalFromExtra(t *testing.T) {
+	interval, err := minReplanIntervalFromExtra(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, interval, test.ShouldEqual, 0)
+
+	interval, err = minReplanIntervalFromExtra(map[string]interface{}{motion.MinReplanIntervalExtraKey: 1.5})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, interval, test.ShouldEqual, time.Millisecond*1500)
+
+	_, err = minReplanIntervalFromExtra(map[string]interface{}{motion.MinReplanIntervalExtraKey: -1.})
+	test.That(t, err, test.ShouldBeError, errors.New("min_replan_interval_sec may not be negative"))
+
+	_, err = minReplanIntervalFromExtra(map[string]interface{}{motion.MinReplanIntervalExtraKey: "soon"})
+	test.That(t, err, test.ShouldBeError, errors.New("could not interpret min_replan_interval_sec field as float"))
+}
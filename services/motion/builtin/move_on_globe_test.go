@@ -3,16 +3,23 @@ package builtin
 import (
 	"context"
 	"math"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/components/base/kinematicbase"
 	_ "go.viam.com/rdk/components/register"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/spatialmath"
 )
@@ -287,6 +294,161 @@ func TestMoveOnGlobe(t *testing.T) {
 		test.That(t, planResp, test.ShouldBeNil)
 	})
 
+	t.Run("succeeds as soon as the base arrives at the goal", func(t *testing.T) {
+		injectedMovementSensor, _, fakeBase, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 50)
+		defer ms.Close(ctx)
+		planDeviationMM := 1000.
+		motionCfg := &motion.MotionConfiguration{PlanDeviationMM: planDeviationMM}
+		arrivalExtra := map[string]interface{}{"arrival_check_freq_hz": 100.}
+		for k, v := range extra {
+			arrivalExtra[k] = v
+		}
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      fakeBase.Name(),
+			Destination:        dst,
+			MovementSensorName: injectedMovementSensor.Name(),
+			MotionCfg:          motionCfg,
+			Extra:              arrivalExtra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+
+		plan, err := planExecutor.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := planExecutor.Execute(ctx, plan)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.Replan, test.ShouldBeFalse)
+
+		// the execution ended within the goal radius, well before the base reached the end of the plan
+		pif, err := fakeBase.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		distanceToGoal := pif.Pose().Point().Distance(expectedDst)
+		test.That(t, distanceToGoal, test.ShouldBeLessThanOrEqualTo, planDeviationMM)
+		test.That(t, distanceToGoal, test.ShouldBeGreaterThan, planDeviationMM/2)
+	})
+
+	t.Run("replans if the base doesn't advance a step within the step timeout", func(t *testing.T) {
+		injectedMovementSensor, _, fakeBase, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 5)
+		defer ms.Close(ctx)
+		stepTimeoutExtra := map[string]interface{}{"step_timeout_sec": 0.1}
+		for k, v := range extra {
+			stepTimeoutExtra[k] = v
+		}
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      fakeBase.Name(),
+			Destination:        dst,
+			MovementSensorName: injectedMovementSensor.Name(),
+			Extra:              stepTimeoutExtra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		stalled := &stalledKinematicBase{KinematicBase: mr.kinematicBase}
+		mr.kinematicBase = stalled
+
+		plan, err := mr.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := mr.Execute(ctx, plan)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.Replan, test.ShouldBeTrue)
+		test.That(t, resp.ReplanReason, test.ShouldEqual, "step timeout")
+		test.That(t, resp.ReplanKind, test.ShouldEqual, motion.ReplanReasonOther)
+		// the blocked GoToInputs was cancelled
+		test.That(t, stalled.cancelled.Load(), test.ShouldBeTrue)
+	})
+
+	t.Run("replans are seeded with the previous plan if seed_replans is set", func(t *testing.T) {
+		injectedMovementSensor, _, fakeBase, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 5)
+		defer ms.Close(ctx)
+		seedExtra := map[string]interface{}{"seed_replans": true}
+		for k, v := range extra {
+			seedExtra[k] = v
+		}
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      fakeBase.Name(),
+			Destination:        dst,
+			MovementSensorName: injectedMovementSensor.Name(),
+			Extra:              seedExtra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		seedPlan, err := planExecutor.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		planExecutor, err = ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, seedPlan, 1)
+		test.That(t, err, test.ShouldBeNil)
+		mr, ok := planExecutor.(*moveRequest)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, mr.seedReplans, test.ShouldBeTrue)
+		test.That(t, mr.seedPlan, test.ShouldEqual, seedPlan)
+		replan, err := mr.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(replan.Path()), test.ShouldBeGreaterThan, 0)
+	})
+
+	t.Run("virtual obstacles added through DoCommand trigger a replan", func(t *testing.T) {
+		injectedMovementSensor, _, fakeBase, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 50)
+		defer ms.Close(ctx)
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      fakeBase.Name(),
+			Destination:        dst,
+			MovementSensorName: injectedMovementSensor.Name(),
+			MotionCfg:          &motion.MotionConfiguration{ObstaclePollingFreqHz: 100, PlanDeviationMM: epsilonMM},
+			Extra:              extra,
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		plan, err := planExecutor.Plan(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		// invalid obstacles & unknown commands are rejected
+		_, err = ms.DoCommand(ctx, map[string]interface{}{"command": "add_virtual_obstacles"})
+		test.That(t, err, test.ShouldBeError, errors.New("missing 'obstacles' value"))
+		_, err = ms.DoCommand(ctx, map[string]interface{}{
+			"command":   "add_virtual_obstacles",
+			"obstacles": []interface{}{map[string]interface{}{"geometries": []interface{}{map[string]interface{}{"r": 10}}}},
+		})
+		test.That(t, err, test.ShouldBeError, errors.New("virtual obstacle 0 has no location"))
+		_, err = ms.DoCommand(ctx, map[string]interface{}{
+			"command": "add_virtual_obstacles",
+			"obstacles": []interface{}{map[string]interface{}{
+				"location":   map[string]interface{}{"latitude": gpsPoint.Lat(), "longitude": gpsPoint.Lng()},
+				"geometries": []interface{}{map[string]interface{}{"type": "box", "x": -1, "y": 1, "z": 1}},
+			}},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.DoCommand(ctx, map[string]interface{}{"command": "fly"})
+		test.That(t, err, test.ShouldBeError, errors.New("no such command: fly"))
+
+		// a wall across the path of the plan
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{
+			"command": "add_virtual_obstacles",
+			"obstacles": []interface{}{map[string]interface{}{
+				"location": map[string]interface{}{"latitude": gpsPoint.Lat(), "longitude": gpsPoint.Lng()},
+				"geometries": []interface{}{map[string]interface{}{
+					"type":        "box",
+					"x":           20,
+					"y":           2000,
+					"z":           10,
+					"translation": map[string]interface{}{"x": 1300, "y": 0, "z": 0},
+				}},
+			}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["virtual_obstacles"], test.ShouldEqual, 1)
+
+		executeResp, err := planExecutor.Execute(ctx, plan)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, executeResp.Replan, test.ShouldBeTrue)
+		test.That(t, executeResp.ReplanKind, test.ShouldEqual, motion.ReplanReasonObstacleDetected)
+
+		resp, err = ms.DoCommand(ctx, map[string]interface{}{"command": "clear_virtual_obstacles"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["virtual_obstacles"], test.ShouldEqual, 0)
+		test.That(t, ms.(*builtIn).currentVirtualObstacles(), test.ShouldBeEmpty)
+	})
+
 	t.Run("check offset constructed correctly", func(t *testing.T) {
 		_, fsSvc, _, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 5)
 		defer ms.Close(ctx)
@@ -297,4 +459,105 @@ func TestMoveOnGlobe(t *testing.T) {
 		}
 		test.That(t, movementSensorToBase.Pose().Point(), test.ShouldResemble, r3.Vector{X: 10, Y: 0, Z: 0})
 	})
+
+	t.Run("the movement sensor offset is computed once & reused until reconfigured", func(t *testing.T) {
+		injectedMovementSensor, fsSvc, fakeBase, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 5)
+		defer ms.Close(ctx)
+		countingFS := &transformPoseCountingService{Service: fsSvc}
+		ms.(*builtIn).fsService = countingFS
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      fakeBase.Name(),
+			Destination:        dst,
+			MovementSensorName: injectedMovementSensor.Name(),
+			Extra:              extra,
+		}
+		for replanCount := 0; replanCount < 3; replanCount++ {
+			_, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, replanCount)
+			test.That(t, err, test.ShouldBeNil)
+		}
+		test.That(t, countingFS.count.Load(), test.ShouldEqual, 1)
+		offset, err := ms.(*builtIn).movementSensorToBase(ctx, fakeBase.Name(), injectedMovementSensor.Name())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, offset.Pose().Point(), test.ShouldResemble, r3.Vector{X: 10, Y: 0, Z: 0})
+
+		// clearing the cache, as Reconfigure does, causes the offset to be recomputed
+		ms.(*builtIn).movementSensorOffsets = nil
+		_, err = ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, countingFS.count.Load(), test.ShouldEqual, 2)
+	})
+
+	t.Run("a missing movement sensor offset is logged or is an error if the offset is required", func(t *testing.T) {
+		injectedMovementSensor, fsSvc, fakeBase, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 5)
+		defer ms.Close(ctx)
+		logger, logs := logging.NewObservedTestLogger(t)
+		ms.(*builtIn).logger = logger
+		errTransform := errors.New("no such frame")
+		ms.(*builtIn).fsService = &transformPoseFailingService{Service: fsSvc, err: errTransform}
+		req := motion.MoveOnGlobeReq{
+			ComponentName:      fakeBase.Name(),
+			Destination:        dst,
+			MovementSensorName: injectedMovementSensor.Name(),
+			Extra:              extra,
+		}
+
+		// by default the movement sensor is assumed to be coincident with the base
+		_, err := ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		warnings := logs.FilterMessageSnippet("assuming they are coincident").FilterLevelExact(zapcore.WarnLevel)
+		test.That(t, warnings.Len(), test.ShouldEqual, 1)
+		test.That(t, warnings.All()[0].Message, test.ShouldContainSubstring, errTransform.Error())
+
+		ms.(*builtIn).requireMovementSensorOffset = true
+		_, err = ms.(*builtIn).newMoveOnGlobeRequest(ctx, req, nil, 0)
+		test.That(t, errors.Is(err, errTransform), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unable to find the offset of movement sensor")
+	})
+}
+
+// transformPoseFailingService returns err from TransformPose of the wrapped framesystem.Service.
+type transformPoseFailingService struct {
+	framesystem.Service
+	err error
+}
+
+func (s *transformPoseFailingService) TransformPose(
+	ctx context.Context,
+	pose *referenceframe.PoseInFrame,
+	dst string,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) (*referenceframe.PoseInFrame, error) {
+	return nil, s.err
+}
+
+// transformPoseCountingService counts the calls to TransformPose of the wrapped framesystem.Service.
+type transformPoseCountingService struct {
+	framesystem.Service
+	count atomic.Int32
+}
+
+func (s *transformPoseCountingService) TransformPose(
+	ctx context.Context,
+	pose *referenceframe.PoseInFrame,
+	dst string,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) (*referenceframe.PoseInFrame, error) {
+	s.count.Add(1)
+	return s.Service.TransformPose(ctx, pose, dst, additionalTransforms)
+}
+
+// stalledKinematicBase is a kinematic base whose GoToInputs never makes progress.
+type stalledKinematicBase struct {
+	kinematicbase.KinematicBase
+	cancelled atomic.Bool
+}
+
+func (s *stalledKinematicBase) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	<-ctx.Done()
+	s.cancelled.Store(true)
+	return ctx.Err()
+}
+
+func (s *stalledKinematicBase) ExecutionState(ctx context.Context) (motionplan.ExecutionState, error) {
+	return motionplan.ExecutionState{}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +14,8 @@ import (
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/base/kinematicbase"
+	"go.viam.com/rdk/components/generic"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
@@ -413,6 +416,110 @@ func TestMoveOnMap(t *testing.T) {
 		test.That(t, executionID, test.ShouldResemble, uuid.Nil)
 	})
 
+	t.Run("concurrent moves for the same base are rejected if reject_if_moving is set", func(t *testing.T) {
+		_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+		defer ms.Close(ctx)
+
+		req := motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewPoseFromPoint(r3.Vector{X: 1.32 * 1000}),
+			SlamName:      slam.Named("test_slam"),
+			Extra:         map[string]interface{}{"smooth_iter": 5, motion.RejectIfMovingExtraKey: true},
+		}
+
+		timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+		defer timeoutFn()
+		var wg sync.WaitGroup
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := ms.(*builtIn).MoveOnMap(timeoutCtx, req)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		var succeeded, rejected int
+		for err := range errs {
+			if err == nil {
+				succeeded++
+			} else if errors.Is(err, motion.ErrBaseAlreadyMoving) {
+				rejected++
+			}
+		}
+		test.That(t, succeeded, test.ShouldEqual, 1)
+		test.That(t, rejected, test.ShouldEqual, 1)
+	})
+
+	t.Run("moves rejected by reject_if_moving don't plan", func(t *testing.T) {
+		_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+		defer ms.Close(ctx)
+
+		timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+		defer timeoutFn()
+		_, err := ms.(*builtIn).MoveOnMap(timeoutCtx, motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewPoseFromPoint(r3.Vector{X: 1.32 * 1000}),
+			SlamName:      slam.Named("test_slam"),
+			Extra:         map[string]interface{}{"smooth_iter": 5},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		// planning a move to the base's current position would fail with ErrGoalWithinPlanDeviation
+		executionID, err := ms.(*builtIn).MoveOnMap(timeoutCtx, motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewZeroPose(),
+			SlamName:      slam.Named("test_slam"),
+			MotionCfg:     &motion.MotionConfiguration{},
+			Extra:         map[string]interface{}{"motion_profile": "position_only", motion.RejectIfMovingExtraKey: true},
+		})
+		test.That(t, errors.Is(err, motion.ErrBaseAlreadyMoving), test.ShouldBeTrue)
+		test.That(t, errors.Is(err, motion.ErrGoalWithinPlanDeviation), test.ShouldBeFalse)
+		test.That(t, executionID, test.ShouldResemble, uuid.Nil)
+	})
+
+	t.Run("reject_if_moving must be a bool", func(t *testing.T) {
+		_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+		defer ms.Close(ctx)
+
+		_, err := ms.(*builtIn).MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewPoseFromPoint(r3.Vector{X: 1.32 * 1000}),
+			SlamName:      slam.Named("test_slam"),
+			Extra:         map[string]interface{}{motion.RejectIfMovingExtraKey: "yes"},
+		})
+		test.That(t, err, test.ShouldBeError, errors.New("could not interpret reject_if_moving field as bool"))
+	})
+
+	t.Run("arrival is judged against the arrival radius rather than the plan deviation", func(t *testing.T) {
+		_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+		defer ms.Close(ctx)
+
+		// the goal is 500mm away, beyond the plan deviation but within the arrival radius
+		req := motion.MoveOnMapReq{
+			ComponentName: base.Named("test-base"),
+			Destination:   spatialmath.NewPoseFromPoint(r3.Vector{X: 500}),
+			SlamName:      slam.Named("test_slam"),
+			MotionCfg:     &motion.MotionConfiguration{PlanDeviationMM: 100},
+			Extra:         map[string]interface{}{"motion_profile": "position_only"},
+		}
+		planExecutor, err := ms.(*builtIn).newMoveOnMapRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		arrived, err := planExecutor.(*moveRequest).arrivedAtGoal(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, arrived, test.ShouldBeFalse)
+
+		req.Extra = map[string]interface{}{"motion_profile": "position_only", "arrival_radius_mm": 1000.}
+		planExecutor, err = ms.(*builtIn).newMoveOnMapRequest(ctx, req, nil, 0)
+		test.That(t, err, test.ShouldBeNil)
+		arrived, err = planExecutor.(*moveRequest).arrivedAtGoal(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, arrived, test.ShouldBeTrue)
+	})
+
 	t.Run("pass when within plan dev m of goal without position_only due to theta difference in goal", func(t *testing.T) {
 		_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
 		defer ms.Close(ctx)
@@ -679,3 +786,60 @@ func TestMoveOnMapStaticObs(t *testing.T) {
 		test.That(t, err, test.ShouldBeError, errors.New("context deadline exceeded"))
 	})
 }
+
+// navigableComponent is a component registered under a non-base API which can still be navigated.
+type navigableComponent struct {
+	kinematicbase.KinematicBase
+}
+
+func TestMoveOnMapNavigableComponent(t *testing.T) {
+	ctx := context.Background()
+	kb, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+	defer ms.Close(ctx)
+
+	rover := navigableComponent{kb}
+	roverName := generic.Named("rover")
+	ms.(*builtIn).mu.Lock()
+	ms.(*builtIn).components[roverName] = rover
+	ms.(*builtIn).mu.Unlock()
+
+	goalInBaseFrame := spatialmath.NewPoseFromPoint(r3.Vector{X: 0.277 * 1000, Y: 0.593 * 1000})
+	req := motion.MoveOnMapReq{
+		ComponentName: roverName,
+		Destination:   spatialmath.PoseBetweenInverse(motion.SLAMOrientationAdjustment, goalInBaseFrame),
+		SlamName:      slam.Named("test_slam"),
+		Extra:         map[string]interface{}{"smooth_iter": 0},
+	}
+
+	timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+	defer timeoutFn()
+	executionID, err := ms.(*builtIn).MoveOnMap(timeoutCtx, req)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, executionID, test.ShouldNotResemble, uuid.Nil)
+
+	timeoutCtx, timeoutFn = context.WithTimeout(ctx, time.Second*5)
+	defer timeoutFn()
+	err = motion.PollHistoryUntilSuccessOrError(timeoutCtx, ms, time.Millisecond*5, motion.PlanHistoryReq{
+		ComponentName: roverName,
+		ExecutionID:   executionID,
+		LastPlanOnly:  true,
+	})
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestNavigableAsBase(t *testing.T) {
+	ctx := context.Background()
+	kb, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+	defer ms.Close(ctx)
+
+	// a base is wrapped as it is
+	test.That(t, navigableAsBase(kb), test.ShouldEqual, kb)
+
+	// a component with only the methods motion uses can't have its power set
+	nonBase := struct{ motion.Navigable }{kb}
+	_, isBase := interface{}(nonBase).(base.Base)
+	test.That(t, isBase, test.ShouldBeFalse)
+	b := navigableAsBase(nonBase)
+	test.That(t, b.Name(), test.ShouldResemble, kb.Name())
+	test.That(t, b.SetPower(ctx, r3.Vector{}, r3.Vector{}, nil), test.ShouldNotBeNil)
+}
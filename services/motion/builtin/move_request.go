@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 	goutils "go.viam.com/utils"
 
@@ -66,10 +67,21 @@ type moveRequest struct {
 	kinematicBase     kinematicbase.KinematicBase
 	obstacleDetectors map[vision.Service][]resource.Name
 	replanCostFactor  float64
+	seedReplans       bool
 	fsService         framesystem.Service
+	// virtualObstacles is only set if requestType == requestTypeMoveOnGlobe
+	virtualObstacles func() []*spatialmath.GeoGeometry
 
 	executeBackgroundWorkers *sync.WaitGroup
 	responseChan             chan moveResponse
+	arrivalChan              chan moveResponse
+	stepTimeoutChan          chan moveResponse
+	// arrivalCheckFreq is how often arrival at the goal is checked during execution, zero if it isn't
+	arrivalCheckFreq time.Duration
+	// arrivalRadiusMM is how close the base must be to the goal to have arrived
+	arrivalRadiusMM float64
+	// stepTimeout is how long the base may take to execute a single step of the plan, zero if unbounded
+	stepTimeout time.Duration
 	// replanners for the move request
 	// if we ever have to add additional instances we should figure out how to make this more scalable
 	position, obstacle *replanner
@@ -104,6 +116,9 @@ func (mr *moveRequest) Plan(ctx context.Context) (motionplan.Plan, error) {
 			gifs = append(gifs, transientGifs)
 		}
 	}
+	if virtualGifs := mr.virtualObstaclesInWorld(); virtualGifs != nil {
+		gifs = append(gifs, virtualGifs)
+	}
 	gifs = append(gifs, existingGifs)
 
 	// update worldstate to include transient detections
@@ -113,7 +128,11 @@ func (mr *moveRequest) Plan(ctx context.Context) (motionplan.Plan, error) {
 		return nil, err
 	}
 
-	// TODO(RSDK-5634): this should pass in mr.seedplan and the appropriate replanCostFactor once this bug is found and fixed.
+	// TODO(RSDK-5634): this should always pass in mr.seedplan and the appropriate replanCostFactor once this bug is found and fixed.
+	// Until then seeding replans with the previous plan is opt in.
+	if mr.seedReplans && mr.seedPlan != nil {
+		return motionplan.Replan(ctx, &planRequestCopy, mr.seedPlan, mr.replanCostFactor)
+	}
 	return motionplan.Replan(ctx, &planRequestCopy, nil, 0)
 }
 
@@ -126,6 +145,11 @@ func (mr *moveRequest) Execute(ctx context.Context, plan motionplan.Plan) (state
 	return mr.listen(cancelCtx)
 }
 
+// Stop is a no-op as Execute returns once its context is cancelled.
+func (mr *moveRequest) Stop() error {
+	return nil
+}
+
 func (mr *moveRequest) AnchorGeoPose() *spatialmath.GeoPose {
 	return mr.geoPoseOrigin
 }
@@ -139,6 +163,9 @@ func (mr *moveRequest) execute(ctx context.Context, plan motionplan.Plan) (state
 	}
 
 	if err := mr.kinematicBase.GoToInputs(ctx, waypoints...); err != nil {
+		if errors.Is(err, motion.ErrStaleSLAMPosition) {
+			return mr.pauseForStaleSLAMPosition()
+		}
 		// If there is an error on GoToInputs, stop the component if possible before returning the error
 		mr.logger.CDebugf(ctx, "calling kinematicBase.Stop due to %s\n", err)
 		if stopErr := mr.stop(); stopErr != nil {
@@ -156,16 +183,33 @@ func (mr *moveRequest) execute(ctx context.Context, plan motionplan.Plan) (state
 func (mr *moveRequest) deviatedFromPlan(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
 	errorState, err := mr.kinematicBase.ErrorState(ctx)
 	if err != nil {
+		if errors.Is(err, motion.ErrStaleSLAMPosition) {
+			return mr.pauseForStaleSLAMPosition()
+		}
 		return state.ExecuteResponse{}, err
 	}
 	if errorState.Point().Norm() > mr.config.planDeviationMM {
 		msg := "error state exceeds planDeviationMM; planDeviationMM: %f, errorstate.Point().Norm(): %f, errorstate.Point(): %#v "
 		reason := fmt.Sprintf(msg, mr.config.planDeviationMM, errorState.Point().Norm(), errorState.Point())
-		return state.ExecuteResponse{Replan: true, ReplanReason: reason}, nil
+		return state.ExecuteResponse{Replan: true, ReplanReason: reason, ReplanKind: motion.ReplanReasonPositionDrift}, nil
 	}
 	return state.ExecuteResponse{}, nil
 }
 
+// pauseForStaleSLAMPosition stops the base so that the slam service can relocalize, and requests a replan.
+// As the position of a stopped base isn't considered stale, the replan starts from the position the slam
+// service reports then.
+func (mr *moveRequest) pauseForStaleSLAMPosition() (state.ExecuteResponse, error) {
+	if err := mr.stop(); err != nil {
+		return state.ExecuteResponse{}, err
+	}
+	return state.ExecuteResponse{
+		Replan:       true,
+		ReplanReason: motion.ErrStaleSLAMPosition.Error(),
+		ReplanKind:   motion.ReplanReasonOther,
+	}, nil
+}
+
 // getTransientDetections returns a list of geometries as observed by the provided vision service and camera.
 // Depending on the caller, the geometries returned are either in their relative position
 // with respect to the base or in their absolute position with respect to the world.
@@ -240,6 +284,9 @@ func (mr *moveRequest) obstaclesIntersectPlan(
 	// get the current position of the base
 	currentPosition, err := mr.kinematicBase.CurrentPosition(ctx)
 	if err != nil {
+		if errors.Is(err, motion.ErrStaleSLAMPosition) {
+			return mr.pauseForStaleSLAMPosition()
+		}
 		return state.ExecuteResponse{}, err
 	}
 
@@ -252,59 +299,95 @@ func (mr *moveRequest) obstaclesIntersectPlan(
 				return state.ExecuteResponse{}, err
 			}
 			if len(gifs.Geometries()) == 0 {
-				mr.logger.CDebug(ctx, "will not check if obstacles intersect path since nothing was detected")
-				return state.ExecuteResponse{}, nil
+				mr.logger.CDebugf(ctx, "will not check if obstacles intersect path since nothing was detected by %s", camName)
+				continue
 			}
 
-			// construct new worldstate
-			worldState, err := referenceframe.NewWorldState([]*referenceframe.GeometriesInFrame{existingGifs, gifs}, nil)
-			if err != nil {
-				return state.ExecuteResponse{}, err
+			resp, err := mr.checkPlanAgainstObstacles(ctx, existingGifs, gifs)
+			if err != nil || resp.Replan {
+				return resp, err
 			}
+		}
+	}
 
-			// get the execution state of the base
-			baseExecutionState, err := mr.kinematicBase.ExecutionState(ctx)
-			if err != nil {
-				return state.ExecuteResponse{}, err
-			}
+	// virtual obstacles are checked as if they had been detected
+	if virtualGifs := mr.virtualObstaclesInWorld(); virtualGifs != nil {
+		return mr.checkPlanAgainstObstacles(ctx, existingGifs, virtualGifs)
+	}
+	return state.ExecuteResponse{}, nil
+}
 
-			// build representation of frame system's inputs
-			// TODO(pl): in the case where we have e.g. an arm (not moving) mounted on a base, we should be passing its current
-			// configuration rather than the zero inputs
-			inputMap := referenceframe.StartPositions(mr.planRequest.FrameSystem)
-			inputMap[mr.kinematicBase.Name().ShortName()] = baseExecutionState.CurrentInputs()[mr.kinematicBase.Name().ShortName()]
-			executionState, err := motionplan.NewExecutionState(
-				baseExecutionState.Plan(),
-				baseExecutionState.Index(),
-				inputMap,
-				baseExecutionState.CurrentPoses(),
-			)
-			if err != nil {
-				return state.ExecuteResponse{}, err
-			}
+// checkPlanAgainstObstacles reports whether the obstacles in gifs, along with the existing obstacles of the
+// worldstate, would cause a collision with the executor following the remainder of its Plan.
+func (mr *moveRequest) checkPlanAgainstObstacles(
+	ctx context.Context,
+	existingGifs, gifs *referenceframe.GeometriesInFrame,
+) (state.ExecuteResponse, error) {
+	// construct new worldstate
+	worldState, err := referenceframe.NewWorldState([]*referenceframe.GeometriesInFrame{existingGifs, gifs}, nil)
+	if err != nil {
+		return state.ExecuteResponse{}, err
+	}
 
-			mr.logger.CDebugf(ctx, "CheckPlan inputs: \n currentPosition: %v\n currentInputs: %v\n worldstate: %s",
-				spatialmath.PoseToProtobuf(executionState.CurrentPoses()[mr.kinematicBase.Name().ShortName()].Pose()),
-				inputMap,
-				worldState.String(),
-			)
-
-			if err := motionplan.CheckPlan(
-				mr.kinematicBase.Kinematics(), // frame we wish to check for collisions
-				executionState,
-				worldState, // detected obstacles by this instance of camera + service
-				mr.planRequest.FrameSystem,
-				lookAheadDistanceMM,
-				mr.planRequest.Logger,
-			); err != nil {
-				mr.planRequest.Logger.CInfo(ctx, err.Error())
-				return state.ExecuteResponse{Replan: true, ReplanReason: err.Error()}, nil
-			}
-		}
+	// get the execution state of the base
+	baseExecutionState, err := mr.kinematicBase.ExecutionState(ctx)
+	if err != nil {
+		return state.ExecuteResponse{}, err
+	}
+
+	// build representation of frame system's inputs
+	// TODO(pl): in the case where we have e.g. an arm (not moving) mounted on a base, we should be passing its current
+	// configuration rather than the zero inputs
+	inputMap := referenceframe.StartPositions(mr.planRequest.FrameSystem)
+	inputMap[mr.kinematicBase.Name().ShortName()] = baseExecutionState.CurrentInputs()[mr.kinematicBase.Name().ShortName()]
+	executionState, err := motionplan.NewExecutionState(
+		baseExecutionState.Plan(),
+		baseExecutionState.Index(),
+		inputMap,
+		baseExecutionState.CurrentPoses(),
+	)
+	if err != nil {
+		return state.ExecuteResponse{}, err
+	}
+
+	mr.logger.CDebugf(ctx, "CheckPlan inputs: \n currentPosition: %v\n currentInputs: %v\n worldstate: %s",
+		spatialmath.PoseToProtobuf(executionState.CurrentPoses()[mr.kinematicBase.Name().ShortName()].Pose()),
+		inputMap,
+		worldState.String(),
+	)
+
+	if err := motionplan.CheckPlan(
+		mr.kinematicBase.Kinematics(), // frame we wish to check for collisions
+		executionState,
+		worldState, // detected or virtual obstacles
+		mr.planRequest.FrameSystem,
+		lookAheadDistanceMM,
+		mr.planRequest.Logger,
+	); err != nil {
+		mr.planRequest.Logger.CInfo(ctx, err.Error())
+		return state.ExecuteResponse{
+			Replan:       true,
+			ReplanReason: err.Error(),
+			ReplanKind:   motion.ReplanReasonObstacleDetected,
+		}, nil
 	}
 	return state.ExecuteResponse{}, nil
 }
 
+// virtualObstaclesInWorld returns the virtual obstacles added through DoCommand, relative to the origin of
+// a MoveOnGlobe request, or nil if there are none.
+func (mr *moveRequest) virtualObstaclesInWorld() *referenceframe.GeometriesInFrame {
+	if mr.virtualObstacles == nil || mr.geoPoseOrigin == nil {
+		return nil
+	}
+	obstacles := mr.virtualObstacles()
+	if len(obstacles) == 0 {
+		return nil
+	}
+	geoms := spatialmath.GeoGeometriesToGeometries(obstacles, mr.geoPoseOrigin.Location())
+	return referenceframe.NewGeometriesInFrame(referenceframe.World, geoms)
+}
+
 func kbOptionsFromCfg(motionCfg *validatedMotionConfiguration, validatedExtra validatedExtra) kinematicbase.Options {
 	kinematicsOptions := kinematicbase.NewKinematicBaseOptions()
 
@@ -422,6 +505,48 @@ func newValidatedMotionCfg(motionCfg *motion.MotionConfiguration, reqType reques
 	return vmc, nil
 }
 
+type movementSensorOffsetKey struct {
+	componentName      resource.Name
+	movementSensorName resource.Name
+}
+
+// movementSensorToBase returns the pose of the base in the frame of its movement sensor. As the offset
+// is constant unless the frame system changes it is cached per base & movement sensor.
+// If the offset can't be found an error is returned if the service requires the offset, otherwise
+// the movement sensor is assumed to be coincident with the base.
+func (ms *builtIn) movementSensorToBase(
+	ctx context.Context,
+	componentName, movementSensorName resource.Name,
+) (*referenceframe.PoseInFrame, error) {
+	key := movementSensorOffsetKey{componentName: componentName, movementSensorName: movementSensorName}
+	ms.movementSensorOffsetsMu.Lock()
+	offset, ok := ms.movementSensorOffsets[key]
+	ms.movementSensorOffsetsMu.Unlock()
+	if ok {
+		return offset, nil
+	}
+
+	baseOrigin := referenceframe.NewPoseInFrame(componentName.ShortName(), spatialmath.NewZeroPose())
+	offset, err := ms.fsService.TransformPose(ctx, baseOrigin, movementSensorName.ShortName(), nil)
+	if err != nil {
+		if ms.requireMovementSensorOffset {
+			return nil, errors.Wrapf(err, "unable to find the offset of movement sensor %s from base %s", movementSensorName, componentName)
+		}
+		// the fallback isn't cached so that the offset is found once it becomes available
+		ms.logger.CWarnf(ctx, "unable to find the offset of movement sensor %s from base %s, assuming they are coincident: %s",
+			movementSensorName, componentName, err.Error())
+		return baseOrigin, nil
+	}
+
+	ms.movementSensorOffsetsMu.Lock()
+	defer ms.movementSensorOffsetsMu.Unlock()
+	if ms.movementSensorOffsets == nil {
+		ms.movementSensorOffsets = map[movementSensorOffsetKey]*referenceframe.PoseInFrame{}
+	}
+	ms.movementSensorOffsets[key] = offset
+	return offset, nil
+}
+
 func (ms *builtIn) newMoveOnGlobeRequest(
 	ctx context.Context,
 	req motion.MoveOnGlobeReq,
@@ -475,11 +600,9 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	}
 
 	// add an offset between the movement sensor and the base if it is applicable
-	baseOrigin := referenceframe.NewPoseInFrame(req.ComponentName.ShortName(), spatialmath.NewZeroPose())
-	movementSensorToBase, err := ms.fsService.TransformPose(ctx, baseOrigin, movementSensor.Name().ShortName(), nil)
+	movementSensorToBase, err := ms.movementSensorToBase(ctx, req.ComponentName, movementSensor.Name())
 	if err != nil {
-		// here we make the assumption the movement sensor is coincident with the base
-		movementSensorToBase = baseOrigin
+		return nil, err
 	}
 	// Create a localizer from the movement sensor, and collapse reported orientations to 2d
 	localizer := motion.TwoDLocalizer(motion.NewMovementSensorLocalizer(movementSensor, origin, movementSensorToBase.Pose()))
@@ -489,9 +612,9 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	if !ok {
 		return nil, resource.NewNotFoundError(req.ComponentName)
 	}
-	b, ok := baseComponent.(base.Base)
+	b, ok := baseComponent.(motion.Navigable)
 	if !ok {
-		return nil, fmt.Errorf("cannot move component of type %T because it is not a Base", baseComponent)
+		return nil, motion.NewNotBaseError(req.ComponentName, baseComponent)
 	}
 
 	fs, err := ms.fsService.FrameSystem(ctx, nil)
@@ -513,7 +636,7 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 		{Min: -straightlineDistance * 3, Max: straightlineDistance * 3},
 		{Min: -2 * math.Pi, Max: 2 * math.Pi},
 	} // Note: this is only for diff drive, not used for PTGs
-	kb, err := kinematicbase.WrapWithKinematics(ctx, b, ms.logger, localizer, limits, kinematicsOptions)
+	kb, err := kinematicbase.WrapWithKinematics(ctx, navigableAsBase(b), ms.logger, localizer, limits, kinematicsOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -537,6 +660,7 @@ func (ms *builtIn) newMoveOnGlobeRequest(
 	mr.replanCostFactor = valExtra.replanCostFactor
 	mr.requestType = requestTypeMoveOnGlobe
 	mr.geoPoseOrigin = spatialmath.NewGeoPose(origin, heading)
+	mr.virtualObstacles = ms.currentVirtualObstacles
 	return mr, nil
 }
 
@@ -583,24 +707,33 @@ func (ms *builtIn) newMoveOnMapRequest(
 	// create a KinematicBase from the componentName
 	component, ok := ms.components[req.ComponentName]
 	if !ok {
-		return nil, resource.DependencyNotFoundError(req.ComponentName)
+		return nil, resource.NewNotFoundError(req.ComponentName)
 	}
-	b, ok := component.(base.Base)
+	b, ok := component.(motion.Navigable)
 	if !ok {
-		return nil, fmt.Errorf("cannot move component of type %T because it is not a Base", component)
+		return nil, motion.NewNotBaseError(req.ComponentName, component)
 	}
 
 	// build kinematic options
 	kinematicsOptions := kbOptionsFromCfg(motionCfg, valExtra)
+	// MoveOnMap may judge arrival independently of plan deviation
+	if valExtra.arrivalRadiusMM > 0 {
+		kinematicsOptions.GoalRadiusMM = valExtra.arrivalRadiusMM
+	}
 
 	fs, err := ms.fsService.FrameSystem(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a localizer from the movement sensor, and collapse reported orientations to 2d
-	localizer := motion.TwoDLocalizer(motion.NewSLAMLocalizer(slamSvc))
-	kb, err := kinematicbase.WrapWithKinematics(ctx, b, ms.logger, localizer, limits, kinematicsOptions)
+	// Create a localizer from the slam service, and collapse reported orientations to 2d
+	var slamOpts []motion.SLAMLocalizerOption
+	if valExtra.staleSLAMPositionTimeoutSec > 0 {
+		staleTimeout := time.Duration(valExtra.staleSLAMPositionTimeoutSec * float64(time.Second))
+		slamOpts = append(slamOpts, motion.WithStalePositionTimeout(staleTimeout, b))
+	}
+	localizer := motion.TwoDLocalizer(motion.NewSLAMLocalizer(slamSvc, slamOpts...))
+	kb, err := kinematicbase.WrapWithKinematics(ctx, navigableAsBase(b), ms.logger, localizer, limits, kinematicsOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -634,6 +767,9 @@ func (ms *builtIn) newMoveOnMapRequest(
 		return nil, err
 	}
 	mr.requestType = requestTypeMoveOnMap
+	if valExtra.arrivalRadiusMM > 0 {
+		mr.arrivalRadiusMM = valExtra.arrivalRadiusMM
+	}
 	return mr, nil
 }
 
@@ -728,6 +864,13 @@ func (ms *builtIn) createBaseMoveRequest(
 		obstaclePollingFreq = time.Duration(1000/motionCfg.obstaclePollingFreqHz) * time.Millisecond
 	}
 
+	var arrivalCheckFreq time.Duration
+	if valExtra.arrivalCheckFreqHz > 0 {
+		arrivalCheckFreq = time.Duration(1000/valExtra.arrivalCheckFreqHz) * time.Millisecond
+	}
+
+	stepTimeout := time.Duration(valExtra.stepTimeoutSec * float64(time.Second))
+
 	mr := &moveRequest{
 		config: motionCfg,
 		logger: ms.logger,
@@ -744,12 +887,18 @@ func (ms *builtIn) createBaseMoveRequest(
 		poseOrigin:        startPose,
 		kinematicBase:     kb,
 		replanCostFactor:  valExtra.replanCostFactor,
+		seedReplans:       valExtra.seedReplans,
 		obstacleDetectors: obstacleDetectors,
 		fsService:         ms.fsService,
 
 		executeBackgroundWorkers: &backgroundWorkers,
 
-		responseChan: make(chan moveResponse, 1),
+		responseChan:     make(chan moveResponse, 1),
+		arrivalChan:      make(chan moveResponse, 1),
+		arrivalCheckFreq: arrivalCheckFreq,
+		arrivalRadiusMM:  motionCfg.planDeviationMM,
+		stepTimeoutChan:  make(chan moveResponse, 1),
+		stepTimeout:      stepTimeout,
 	}
 
 	// TODO: Change deviatedFromPlan to just query positionPollingFreq on the struct & the same for the obstaclesIntersectPlan
@@ -781,6 +930,20 @@ func (mr *moveRequest) start(ctx context.Context, plan motionplan.Plan) {
 		mr.obstacle.startPolling(ctx, plan)
 	}, mr.executeBackgroundWorkers.Done)
 
+	if mr.arrivalCheckFreq > 0 {
+		mr.executeBackgroundWorkers.Add(1)
+		goutils.ManagedGo(func() {
+			mr.pollArrival(ctx)
+		}, mr.executeBackgroundWorkers.Done)
+	}
+
+	if mr.stepTimeout > 0 {
+		mr.executeBackgroundWorkers.Add(1)
+		goutils.ManagedGo(func() {
+			mr.pollStepTimeout(ctx)
+		}, mr.executeBackgroundWorkers.Done)
+	}
+
 	// spawn function to execute the plan on the robot
 	mr.executeBackgroundWorkers.Add(1)
 	goutils.ManagedGo(func() {
@@ -807,9 +970,95 @@ func (mr *moveRequest) listen(ctx context.Context) (state.ExecuteResponse, error
 	case resp := <-mr.obstacle.responseChan:
 		mr.logger.CDebugf(ctx, "obstacle response: %s", resp)
 		return resp.executeResponse, resp.err
+
+	case resp := <-mr.arrivalChan:
+		mr.logger.CDebugf(ctx, "arrival response: %s", resp)
+		return resp.executeResponse, resp.err
+
+	case resp := <-mr.stepTimeoutChan:
+		mr.logger.CDebugf(ctx, "step timeout response: %s", resp)
+		return resp.executeResponse, resp.err
+	}
+}
+
+// pollArrival checks whether the base has arrived at the goal every arrivalCheckFreq and responds
+// on arrivalChan as soon as it has, ending the execution even if the plan hasn't been fully executed.
+func (mr *moveRequest) pollArrival(ctx context.Context) {
+	ticker := time.NewTicker(mr.arrivalCheckFreq)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			arrived, err := mr.arrivedAtGoal(ctx)
+			// a stale position pauses the execution through the position replanner
+			if errors.Is(err, motion.ErrStaleSLAMPosition) {
+				continue
+			}
+			if err != nil || arrived {
+				mr.arrivalChan <- moveResponse{err: err}
+				return
+			}
+		}
+	}
+}
+
+// pollStepTimeout watches the step of the plan the base is executing and, if the base hasn't advanced
+// to the next step within stepTimeout, responds on stepTimeoutChan requesting a replan. Responding ends the
+// execution, which cancels the in progress GoToInputs call.
+// Bases which don't report their execution state aren't watched.
+func (mr *moveRequest) pollStepTimeout(ctx context.Context) {
+	pollInterval := mr.stepTimeout / 10
+	if pollInterval <= 0 {
+		pollInterval = mr.stepTimeout
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastIndex := -1
+	lastProgress := time.Now()
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			executionState, err := mr.kinematicBase.ExecutionState(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				mr.logger.CWarnf(ctx, "not enforcing the step timeout as the execution state of %s is unavailable: %s",
+					mr.kinematicBase.Name(), err)
+				return
+			}
+			if index := executionState.Index(); index != lastIndex {
+				lastIndex = index
+				lastProgress = time.Now()
+				continue
+			}
+			if time.Since(lastProgress) >= mr.stepTimeout {
+				mr.stepTimeoutChan <- moveResponse{executeResponse: state.ExecuteResponse{
+					Replan:       true,
+					ReplanReason: "step timeout",
+					ReplanKind:   motion.ReplanReasonOther,
+				}}
+				return
+			}
+		}
 	}
 }
 
+// arrivedAtGoal returns whether the base is within arrivalRadiusMM of the goal.
+func (mr *moveRequest) arrivedAtGoal(ctx context.Context) (bool, error) {
+	currentPosition, err := mr.kinematicBase.CurrentPosition(ctx)
+	if err != nil {
+		return false, err
+	}
+	return spatialmath.PoseAlmostCoincidentEps(mr.planRequest.Goal.Pose(), currentPosition.Pose(), mr.arrivalRadiusMM), nil
+}
+
 func (mr *moveRequest) stop() error {
 	stopCtx, cancelFn := context.WithTimeout(context.Background(), baseStopTimeout)
 	defer cancelFn()
@@ -819,3 +1068,20 @@ func (mr *moveRequest) stop() error {
 	}
 	return nil
 }
+
+// navigableAsBase returns n as the base.Base which kinematic bases wrap. Navigable components which
+// aren't bases can't have their power set, which motion never does.
+func navigableAsBase(n motion.Navigable) base.Base {
+	if b, ok := n.(base.Base); ok {
+		return b
+	}
+	return navigableBase{n}
+}
+
+type navigableBase struct {
+	motion.Navigable
+}
+
+func (nb navigableBase) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	return errors.Errorf("cannot set the power of %s as it is not a base", nb.Name())
+}
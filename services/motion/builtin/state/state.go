@@ -22,10 +22,47 @@ import (
 	"go.viam.com/rdk/spatialmath"
 )
 
-// PlannerExecutor implements Plan and Execute.
+// ErrTooManyExecutions is returned by StartExecution when starting a new execution
+// would exceed the State's limit on concurrent executions.
+var ErrTooManyExecutions = errors.New("too many concurrent executions")
+
+// ErrExecutionStopped is the reason given to a plan which was stopped by StopExecutionByResource,
+// StopExecutionByResourceWithTimeout or StopAllExecutions.
+var ErrExecutionStopped = errors.New("execution stopped")
+
+// ErrStateStopped is the reason given to a plan which was stopped because the State was stopped,
+// e.g. when the motion service is closed or reconfigured.
+var ErrStateStopped = errors.New("motion state stopped")
+
+// ErrActiveExecution is returned when starting an execution for a component which already has an
+// active execution that the new one doesn't preempt.
+var ErrActiveExecution = errors.New("there is already an active executionID")
+
+// ErrStopTimeout is returned by StopExecutionByResourceWithTimeout when an execution
+// does not stop within the timeout.
+var ErrStopTimeout = errors.New("timed out waiting for execution to stop")
+
+// PreemptedReason is the reason given to a plan which was stopped because a higher
+// priority execution was started for the same component.
+const PreemptedReason = "preempted"
+
+// defaultReplanReason is the reason a replanned plan's status is given if Execute didn't provide one.
+const defaultReplanReason = "replan triggered without providing a reason"
+
+// TriggeredReplanReason is the reason a plan's status is given when it is replanned due to TriggerReplan.
+const TriggeredReplanReason = "replan triggered by request"
+
+// errPreempted is the cause an execution's context is cancelled with when it is preempted.
+var errPreempted = errors.New(PreemptedReason)
+
+// PlannerExecutor implements Plan, Execute and Stop.
 type PlannerExecutor interface {
 	Plan(ctx context.Context) (motionplan.Plan, error)
 	Execute(context.Context, motionplan.Plan) (ExecuteResponse, error)
+	// Stop is called if the execution is cancelled while Execute is running & must cause
+	// Execute to return. PlannerExecutors which already return from Execute when its
+	// context is cancelled may implement it as a no-op.
+	Stop() error
 	AnchorGeoPose() *spatialmath.GeoPose
 }
 
@@ -35,6 +72,8 @@ type ExecuteResponse struct {
 	Replan bool
 	// Set if Replan is true, describes why replanning was triggered
 	ReplanReason string
+	// Set if Replan is true, categorizes why replanning was triggered
+	ReplanKind motion.ReplanReason
 }
 
 // PlannerExecutorConstructor creates a PlannerExecutor
@@ -77,20 +116,62 @@ type stateUpdateMsg struct {
 type stateExecution struct {
 	id            motion.ExecutionID
 	componentName resource.Name
+	priority      int
+	label         string
 	waitGroup     *sync.WaitGroup
-	cancelFunc    context.CancelFunc
+	cancelFunc    context.CancelCauseFunc
+	replanTrigger chan motion.ReplanReason
+	slot          *executionSlot
 	history       []motion.PlanWithStatus
 }
 
+// an executionSlot is one of the State's concurrent executions, which an execution
+// holds until its goroutine terminates.
+type executionSlot struct {
+	// released is protected by the State's executionsMu
+	released bool
+}
+
 func (e *stateExecution) stop() {
-	e.cancelFunc()
+	e.stopWithCause(ErrExecutionStopped)
+}
+
+// stopWithCause stops the execution, cancelling its context with the given cause.
+func (e *stateExecution) stopWithCause(cause error) {
+	e.cancelFunc(cause)
 	e.waitGroup.Wait()
 }
 
+// stopWithTimeout stops the execution, returning ErrStopTimeout if its goroutine doesn't
+// terminate within the timeout. The execution's context is cancelled regardless.
+func (e *stateExecution) stopWithTimeout(timeout time.Duration) error {
+	e.cancelFunc(ErrExecutionStopped)
+	stopped := make(chan struct{})
+	utils.PanicCapturingGo(func() {
+		e.waitGroup.Wait()
+		close(stopped)
+	})
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		return nil
+	case <-timer.C:
+		return errors.Wrapf(ErrStopTimeout, "execution %s of component %s did not stop within %s", e.id, e.componentName, timeout)
+	}
+}
+
 func (cs componentState) lastExecution() stateExecution {
 	return cs.executionsByID[cs.lastExecutionID()]
 }
 
+// activeExecution returns the last execution & true if it is in a non terminal state.
+func (cs componentState) activeExecution() (stateExecution, bool) {
+	e := cs.lastExecution()
+	_, terminated := motion.TerminalStateSet[e.history[0].StatusHistory[0].State]
+	return e, !terminated
+}
+
 func (cs componentState) lastExecutionID() motion.ExecutionID {
 	return cs.executionIDHistory[0]
 }
@@ -102,9 +183,15 @@ type execution[R any] struct {
 	state                      *State
 	waitGroup                  *sync.WaitGroup
 	cancelCtx                  context.Context
-	cancelFunc                 context.CancelFunc
+	cancelFunc                 context.CancelCauseFunc
 	logger                     logging.Logger
 	componentName              resource.Name
+	priority                   int
+	label                      string
+	planTimeout                time.Duration
+	minReplanInterval          time.Duration
+	replanTrigger              chan motion.ReplanReason
+	slot                       *executionSlot
 	req                        R
 	plannerExecutorConstructor PlannerExecutorConstructor[R]
 }
@@ -120,29 +207,41 @@ func (e *execution[R]) newPlanWithExecutor(ctx context.Context, seedPlan motionp
 	if err != nil {
 		return planWithExecutor{}, err
 	}
-	plan, err := pe.Plan(ctx)
+	planCtx := ctx
+	if e.planTimeout > 0 {
+		var cancel context.CancelFunc
+		planCtx, cancel = context.WithTimeout(ctx, e.planTimeout)
+		defer cancel()
+	}
+	plan, err := pe.Plan(planCtx)
 	if err != nil {
+		if ctx.Err() == nil && errors.Is(planCtx.Err(), context.DeadlineExceeded) {
+			return planWithExecutor{}, fmt.Errorf("planning timed out after %s: %w", e.planTimeout, err)
+		}
 		return planWithExecutor{}, err
 	}
+	// a path & trajectory of different lengths indicates a bug in the planner
+	if plan != nil && len(plan.Trajectory()) > 0 && len(plan.Path()) != len(plan.Trajectory()) {
+		return planWithExecutor{}, fmt.Errorf(
+			"planner returned a misaligned plan for execution %s and component %s: path has %d steps but trajectory has %d",
+			e.id, e.componentName, len(plan.Path()), len(plan.Trajectory()))
+	}
 	return planWithExecutor{
 		plan: motion.PlanWithMetadata{
 			Plan:          plan,
-			ID:            uuid.New(),
+			ID:            e.state.newID(),
 			ExecutionID:   e.id,
 			ComponentName: e.componentName,
 			AnchorGeoPose: pe.AnchorGeoPose(),
+			Label:         e.label,
 		},
 		executor: pe,
 	}, nil
 }
 
 // Start starts an execution with a given plan.
-func (e *execution[R]) start(ctx context.Context) error {
+func (e *execution[R]) start(ctx context.Context, originalPlanWithExecutor planWithExecutor) {
 	var replanCount int
-	originalPlanWithExecutor, err := e.newPlanWithExecutor(ctx, nil, replanCount)
-	if err != nil {
-		return err
-	}
 	e.notifyStateNewExecution(e.toStateExecution(), originalPlanWithExecutor.plan, time.Now())
 	// We need to add to both the state & execution waitgroups
 	// B/c both the state & the stateExecution need to know if this
@@ -157,9 +256,11 @@ func (e *execution[R]) start(ctx context.Context) error {
 	utils.PanicCapturingGo(func() {
 		defer e.state.waitGroup.Done()
 		defer e.waitGroup.Done()
-		defer e.cancelFunc()
+		defer e.state.releaseExecutionSlot(e.slot)
+		defer e.cancelFunc(nil)
 
 		lastPWE := originalPlanWithExecutor
+		lastPlanned := time.Now()
 		// Exit conditions of this loop:
 		// 1. The execution's context was cancelled, which happens if the state's Stop() was called or
 		// StopExecutionByResource was called for this resource
@@ -167,17 +268,23 @@ func (e *execution[R]) start(ctx context.Context) error {
 		// 3. the execution failed
 		// 4. replanning failed
 		for {
-			resp, err := lastPWE.executor.Execute(e.cancelCtx, lastPWE.plan.Plan)
+			// a plan without steps means the component is already at the goal, so there is nothing to execute
+			if isZeroStepPlan(lastPWE.plan.Plan) {
+				e.notifyStatePlanSucceeded(lastPWE.plan, time.Now())
+				return
+			}
+
+			resp, err := e.execute(lastPWE)
 
 			switch {
 			// stopped
 			case errors.Is(err, context.Canceled):
-				e.notifyStatePlanStopped(lastPWE.plan, time.Now())
+				e.notifyStatePlanStopped(lastPWE.plan, e.stoppedReason(), time.Now())
 				return
 
 			// failure
 			case err != nil:
-				e.notifyStatePlanFailed(lastPWE.plan, err.Error(), time.Now())
+				e.notifyStatePlanFailed(lastPWE.plan, err.Error(), motion.ReplanReasonUnspecified, time.Now())
 				return
 
 			// success
@@ -188,6 +295,16 @@ func (e *execution[R]) start(ctx context.Context) error {
 			// replan
 			default:
 				replanCount++
+				if resp.ReplanKind == motion.ReplanReasonUnspecified {
+					resp.ReplanKind = motion.ReplanReasonOther
+				}
+				if resp.ReplanReason == "" {
+					resp.ReplanReason = defaultReplanReason
+				}
+				if !e.waitForReplan(lastPlanned, resp.ReplanKind) {
+					e.notifyStatePlanStopped(lastPWE.plan, e.stoppedReason(), time.Now())
+					return
+				}
 				newPWE, err := e.newPlanWithExecutor(e.cancelCtx, lastPWE.plan.Plan, replanCount)
 				// replan failed
 				if err != nil {
@@ -196,25 +313,110 @@ func (e *execution[R]) start(ctx context.Context) error {
 						"to failed due to error: %s\n"
 					e.logger.CWarnf(ctx, msg, e.id, e.componentName, resp.ReplanReason, lastPWE.plan.ID, err.Error())
 
-					e.notifyStatePlanFailed(lastPWE.plan, err.Error(), time.Now())
+					e.notifyStatePlanFailed(lastPWE.plan, err.Error(), resp.ReplanKind, time.Now())
 					return
 				}
 
-				e.notifyStateReplan(lastPWE.plan, resp.ReplanReason, newPWE.plan, time.Now())
+				e.notifyStateReplan(lastPWE.plan, resp.ReplanReason, resp.ReplanKind, newPWE.plan, time.Now())
 				lastPWE = newPWE
+				lastPlanned = time.Now()
 			}
 		}
 	})
+}
 
-	return nil
+// isZeroStepPlan returns true if the plan was successfully planned but has no steps.
+func isZeroStepPlan(plan motionplan.Plan) bool {
+	return plan != nil && len(plan.Path()) == 0
+}
+
+// execute executes the plan, calling Stop on its executor if the execution is cancelled
+// or a replan is triggered before Execute returns.
+func (e *execution[R]) execute(pwe planWithExecutor) (ExecuteResponse, error) {
+	executeCtx, cancelExecute := context.WithCancel(e.cancelCtx)
+	defer cancelExecute()
+	executeDone := make(chan struct{})
+	stopDone := make(chan struct{})
+	var triggered *motion.ReplanReason
+	utils.PanicCapturingGo(func() {
+		defer close(stopDone)
+		select {
+		case <-e.cancelCtx.Done():
+		case replanKind := <-e.replanTrigger:
+			triggered = &replanKind
+			cancelExecute()
+		case <-executeDone:
+			return
+		}
+		if err := pwe.executor.Stop(); err != nil {
+			e.logger.CWarnf(e.cancelCtx, "failed to stop executor of execution %s: %s", e.id, err.Error())
+		}
+	})
+	resp, err := e.recoveringExecute(executeCtx, pwe)
+	close(executeDone)
+	<-stopDone
+	// the execution being cancelled takes precedence over a triggered replan
+	if triggered != nil && e.cancelCtx.Err() == nil {
+		return ExecuteResponse{Replan: true, ReplanReason: TriggeredReplanReason, ReplanKind: *triggered}, nil
+	}
+	return resp, err
+}
+
+// recoveringExecute calls Execute, returning a panic in Execute as an error so that the plan
+// is failed rather than left in progress.
+func (e *execution[R]) recoveringExecute(ctx context.Context, pwe planWithExecutor) (resp ExecuteResponse, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e.logger.CErrorf(e.cancelCtx, "executor of execution %s panicked: %v", e.id, r)
+			err = fmt.Errorf("executor panicked: %v", r)
+		}
+	}()
+	return pwe.executor.Execute(ctx, pwe.plan.Plan)
+}
+
+// waitForReplan defers a replan until minReplanInterval has elapsed since the last plan was
+// created, so that repeated replan requests don't thrash. Replans due to a detected obstacle
+// are never deferred. Returns false if the execution was cancelled while waiting.
+func (e *execution[R]) waitForReplan(lastPlanned time.Time, replanReason motion.ReplanReason) bool {
+	if replanReason == motion.ReplanReasonObstacleDetected {
+		return true
+	}
+	wait := e.minReplanInterval - time.Since(lastPlanned)
+	if wait <= 0 {
+		return true
+	}
+	e.logger.CDebugf(e.cancelCtx, "deferring replan of execution %s by %s due to the minimum replan interval", e.id, wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-e.cancelCtx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// stoppedReason returns the reason a stopped execution's plan status is given, which is
+// the cause its context was cancelled with.
+func (e *execution[R]) stoppedReason() *string {
+	cause := context.Cause(e.cancelCtx)
+	if cause == nil || errors.Is(cause, context.Canceled) {
+		return nil
+	}
+	reason := cause.Error()
+	return &reason
 }
 
 func (e *execution[R]) toStateExecution() stateExecution {
 	return stateExecution{
 		id:            e.id,
 		componentName: e.componentName,
+		priority:      e.priority,
+		label:         e.label,
 		waitGroup:     e.waitGroup,
 		cancelFunc:    e.cancelFunc,
+		replanTrigger: e.replanTrigger,
+		slot:          e.slot,
 	}
 }
 
@@ -230,7 +432,13 @@ func (e *execution[R]) notifyStateNewExecution(execution stateExecution, plan mo
 	})
 }
 
-func (e *execution[R]) notifyStateReplan(lastPlan motion.PlanWithMetadata, reason string, newPlan motion.PlanWithMetadata, time time.Time) {
+func (e *execution[R]) notifyStateReplan(
+	lastPlan motion.PlanWithMetadata,
+	reason string,
+	replanReason motion.ReplanReason,
+	newPlan motion.PlanWithMetadata,
+	time time.Time,
+) {
 	e.state.mu.Lock()
 	defer e.state.mu.Unlock()
 	// NOTE: We hold the lock for both updateStateNewExecution & updateStateNewPlan to ensure no readers
@@ -239,7 +447,12 @@ func (e *execution[R]) notifyStateReplan(lastPlan motion.PlanWithMetadata, reaso
 		componentName: e.componentName,
 		executionID:   e.id,
 		planID:        lastPlan.ID,
-		planStatus:    motion.PlanStatus{State: motion.PlanStateFailed, Timestamp: time, Reason: &reason},
+		planStatus: motion.PlanStatus{
+			State:        motion.PlanStateFailed,
+			Timestamp:    time,
+			Reason:       &reason,
+			ReplanReason: replanReason,
+		},
 	})
 
 	e.state.updateStateNewPlan(planMsg{
@@ -248,14 +461,24 @@ func (e *execution[R]) notifyStateReplan(lastPlan motion.PlanWithMetadata, reaso
 	})
 }
 
-func (e *execution[R]) notifyStatePlanFailed(plan motion.PlanWithMetadata, reason string, time time.Time) {
+func (e *execution[R]) notifyStatePlanFailed(
+	plan motion.PlanWithMetadata,
+	reason string,
+	replanReason motion.ReplanReason,
+	time time.Time,
+) {
 	e.state.mu.Lock()
 	defer e.state.mu.Unlock()
 	e.state.updateStateStatusUpdate(stateUpdateMsg{
 		componentName: e.componentName,
 		executionID:   e.id,
 		planID:        plan.ID,
-		planStatus:    motion.PlanStatus{State: motion.PlanStateFailed, Timestamp: time, Reason: &reason},
+		planStatus: motion.PlanStatus{
+			State:        motion.PlanStateFailed,
+			Timestamp:    time,
+			Reason:       &reason,
+			ReplanReason: replanReason,
+		},
 	})
 }
 
@@ -270,14 +493,14 @@ func (e *execution[R]) notifyStatePlanSucceeded(plan motion.PlanWithMetadata, ti
 	})
 }
 
-func (e *execution[R]) notifyStatePlanStopped(plan motion.PlanWithMetadata, time time.Time) {
+func (e *execution[R]) notifyStatePlanStopped(plan motion.PlanWithMetadata, reason *string, time time.Time) {
 	e.state.mu.Lock()
 	defer e.state.mu.Unlock()
 	e.state.updateStateStatusUpdate(stateUpdateMsg{
 		componentName: e.componentName,
 		executionID:   e.id,
 		planID:        plan.ID,
-		planStatus:    motion.PlanStatus{State: motion.PlanStateStopped, Timestamp: time},
+		planStatus:    motion.PlanStatus{State: motion.PlanStateStopped, Timestamp: time, Reason: reason},
 	})
 }
 
@@ -286,12 +509,91 @@ func (e *execution[R]) notifyStatePlanStopped(plan motion.PlanWithMetadata, time
 type State struct {
 	waitGroup  *sync.WaitGroup
 	cancelCtx  context.Context
-	cancelFunc context.CancelFunc
+	cancelFunc context.CancelCauseFunc
 	logger     logging.Logger
 	ttl        time.Duration
-	// mu protects the componentStateByComponent
+	newID      func() uuid.UUID
+	// maxExecutions is the maximum number of concurrent executions, 0 means unlimited
+	maxExecutions int
+	// executionsMu protects numExecutions
+	executionsMu  sync.Mutex
+	numExecutions int
+	// maxPlansPerExecution & maxStatusHistoryPerPlan bound the history kept, 0 means unbounded
+	maxPlansPerExecution    int
+	maxStatusHistoryPerPlan int
+	// stuckThreshold is how long a plan may be in progress before its execution is considered stuck, 0 means never
+	stuckThreshold time.Duration
+	// readOnly is true if the State was loaded from a snapshot, in which case no executions may be started
+	readOnly bool
+	// statusChanges is nil unless the State was created with WithStatusChangeCallback
+	statusChanges *statusChangeNotifier
+	// mu protects the componentStateByComponent & startingComponents
 	mu                        sync.RWMutex
 	componentStateByComponent map[resource.Name]componentState
+	// startingComponents are reserved by StartExecutions while it plans their executions, so that
+	// concurrent calls for the same component can't both find it without an active execution &
+	// both start one
+	startingComponents map[resource.Name]struct{}
+}
+
+// Option configures optional behavior of a State.
+type Option func(*State)
+
+// WithIDGenerator sets the function used to generate execution & plan ids.
+// Defaults to uuid.New. Intended for tests which need reproducible ids.
+// newID may be called concurrently from multiple executions.
+func WithIDGenerator(newID func() uuid.UUID) Option {
+	return func(s *State) {
+		s.newID = newID
+	}
+}
+
+// WithMaxExecutions limits the number of executions, across all components,
+// which may be planning or executing at the same time.
+// Defaults to 0, which means unlimited.
+func WithMaxExecutions(maxExecutions int) Option {
+	return func(s *State) {
+		s.maxExecutions = maxExecutions
+	}
+}
+
+// WithMaxPlansPerExecution bounds the number of plans kept in an execution's history.
+// Once exceeded the oldest plans are dropped, except for the execution's first plan.
+// Defaults to 0, which means unbounded. Otherwise must be at least 2.
+func WithMaxPlansPerExecution(maxPlansPerExecution int) Option {
+	return func(s *State) {
+		s.maxPlansPerExecution = maxPlansPerExecution
+	}
+}
+
+// WithMaxStatusHistoryPerPlan bounds the number of statuses kept in a plan's status history.
+// Once exceeded the oldest statuses are dropped, except for the plan's initial in progress status.
+// Defaults to 0, which means unbounded. Otherwise must be at least 2.
+func WithMaxStatusHistoryPerPlan(maxStatusHistoryPerPlan int) Option {
+	return func(s *State) {
+		s.maxStatusHistoryPerPlan = maxStatusHistoryPerPlan
+	}
+}
+
+// WithStuckThreshold sets how long a plan may be in progress before StuckExecutions reports its
+// execution as stuck, e.g. because its executor doesn't return from Execute.
+// Defaults to 0, which means executions are never considered stuck.
+func WithStuckThreshold(stuckThreshold time.Duration) Option {
+	return func(s *State) {
+		s.stuckThreshold = stuckThreshold
+	}
+}
+
+// WithStatusChangeCallback sets a callback which is called whenever a plan moves to a new
+// status, allowing observers to be notified of changes without polling. The callback is
+// called in order from a dedicated goroutine, never while the State's lock is held, so
+// it may call the State's methods.
+func WithStatusChangeCallback(onStatusChange func(motion.PlanStatusWithID)) Option {
+	return func(s *State) {
+		if onStatusChange != nil {
+			s.statusChanges = newStatusChangeNotifier(onStatusChange)
+		}
+	}
 }
 
 // NewState creates a new state.
@@ -302,6 +604,7 @@ func NewState(
 	ttl time.Duration,
 	ttlCheckInterval time.Duration,
 	logger logging.Logger,
+	opts ...Option,
 ) (*State, error) {
 	if ttl == 0 {
 		return nil, errors.New("TTL can't be unset")
@@ -319,15 +622,43 @@ func NewState(
 		return nil, errors.New("TTL can't be lower than the TTLCheckInterval")
 	}
 
-	cancelCtx, cancelFunc := context.WithCancel(context.Background())
+	cancelCtx, cancelFunc := context.WithCancelCause(context.Background())
 	s := State{
 		cancelCtx:                 cancelCtx,
 		cancelFunc:                cancelFunc,
 		waitGroup:                 &sync.WaitGroup{},
 		componentStateByComponent: make(map[resource.Name]componentState),
+		startingComponents:        make(map[resource.Name]struct{}),
 		ttl:                       ttl,
+		newID:                     uuid.New,
 		logger:                    logger,
 	}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	if s.newID == nil {
+		cancelFunc(nil)
+		return nil, errors.New("id generator can't be nil")
+	}
+	if s.maxExecutions < 0 {
+		cancelFunc(nil)
+		return nil, errors.New("max executions can't be negative")
+	}
+	if s.stuckThreshold < 0 {
+		cancelFunc(nil)
+		return nil, errors.New("stuck threshold can't be negative")
+	}
+	if s.maxPlansPerExecution < 0 || s.maxPlansPerExecution == 1 {
+		cancelFunc(nil)
+		return nil, errors.New("max plans per execution must be 0 or at least 2")
+	}
+	if s.maxStatusHistoryPerPlan < 0 || s.maxStatusHistoryPerPlan == 1 {
+		cancelFunc(nil)
+		return nil, errors.New("max status history per plan must be 0 or at least 2")
+	}
+	if s.statusChanges != nil {
+		s.statusChanges.start()
+	}
 	s.waitGroup.Add(1)
 	utils.ManagedGo(func() {
 		ticker := time.NewTicker(ttlCheckInterval)
@@ -351,72 +682,343 @@ func NewState(
 	return &s, nil
 }
 
+// LoadFromSnapshot creates a read only State from previously exported plan histories, e.g. the
+// results of PlanHistory, so that they can be queried offline with PlanHistory, ListPlanStatuses etc.
+// No executions run in the returned State & starting one returns an error. All plans must be in a
+// terminal state. Plans are grouped into executions by ExecutionID & ordered by the time of their
+// first status.
+func LoadFromSnapshot(snapshot []motion.PlanWithStatus) (*State, error) {
+	type snapshotExecution struct {
+		stateExecution
+		firstStatus time.Time
+	}
+	executions := map[motion.ExecutionID]*snapshotExecution{}
+	planIDs := map[motion.PlanID]struct{}{}
+	for _, pws := range snapshot {
+		if len(pws.StatusHistory) == 0 {
+			return nil, fmt.Errorf("plan %s has no statuses", pws.Plan.ID)
+		}
+		if _, terminal := motion.TerminalStateSet[pws.StatusHistory[0].State]; !terminal {
+			return nil, fmt.Errorf("plan %s is not in a terminal state", pws.Plan.ID)
+		}
+		if _, exists := planIDs[pws.Plan.ID]; exists {
+			return nil, fmt.Errorf("plan %s is in the snapshot more than once", pws.Plan.ID)
+		}
+		planIDs[pws.Plan.ID] = struct{}{}
+
+		firstStatus := pws.StatusHistory[len(pws.StatusHistory)-1].Timestamp
+		e, exists := executions[pws.Plan.ExecutionID]
+		if !exists {
+			e = &snapshotExecution{
+				stateExecution: stateExecution{
+					id:            pws.Plan.ExecutionID,
+					componentName: pws.Plan.ComponentName,
+					label:         pws.Plan.Label,
+					waitGroup:     &sync.WaitGroup{},
+					cancelFunc:    func(error) {},
+				},
+				firstStatus: firstStatus,
+			}
+			executions[pws.Plan.ExecutionID] = e
+		} else if e.componentName != pws.Plan.ComponentName {
+			return nil, fmt.Errorf("execution %s has plans for more than one component", e.id)
+		}
+		if firstStatus.Before(e.firstStatus) {
+			e.firstStatus = firstStatus
+		}
+		e.history = append(e.history, pws)
+	}
+
+	// executions & their plans are ordered newest first
+	sorted := maps.Values(executions)
+	slices.SortFunc(sorted, func(a, b *snapshotExecution) int {
+		return b.firstStatus.Compare(a.firstStatus)
+	})
+	componentStateByComponent := map[resource.Name]componentState{}
+	for _, e := range sorted {
+		slices.SortStableFunc(e.history, func(a, b motion.PlanWithStatus) int {
+			return b.StatusHistory[len(b.StatusHistory)-1].Timestamp.Compare(a.StatusHistory[len(a.StatusHistory)-1].Timestamp)
+		})
+		cs, exists := componentStateByComponent[e.componentName]
+		if !exists {
+			cs = componentState{executionsByID: map[motion.ExecutionID]stateExecution{}}
+		}
+		cs.executionIDHistory = append(cs.executionIDHistory, e.id)
+		cs.executionsByID[e.id] = e.stateExecution
+		componentStateByComponent[e.componentName] = cs
+	}
+
+	cancelCtx, cancelFunc := context.WithCancelCause(context.Background())
+	return &State{
+		cancelCtx:                 cancelCtx,
+		cancelFunc:                cancelFunc,
+		waitGroup:                 &sync.WaitGroup{},
+		componentStateByComponent: componentStateByComponent,
+		newID:                     uuid.New,
+		logger:                    logging.NewLogger("motion-state-snapshot"),
+		readOnly:                  true,
+	}, nil
+}
+
+// ExecutionOption configures optional behavior of an execution started by StartExecution
+// or StartExecutions.
+type ExecutionOption func(*executionOptions)
+
+type executionOptions struct {
+	priority          int
+	label             string
+	planTimeout       time.Duration
+	minReplanInterval time.Duration
+}
+
+// WithPriority sets the priority of an execution. Defaults to 0.
+// An execution preempts, i.e. stops, the component's active execution if that
+// execution has a lower priority. The active execution is only preempted once the
+// new execution has planned, so it keeps running if planning fails. The preempted
+// execution's plan is marked stopped with the PreemptedReason.
+func WithPriority(priority int) ExecutionOption {
+	return func(o *executionOptions) {
+		o.priority = priority
+	}
+}
+
+// WithLabel sets a label on an execution, which is returned with its plans & plan statuses
+// and which ListPlanStatuses can filter by.
+func WithLabel(label string) ExecutionOption {
+	return func(o *executionOptions) {
+		o.label = label
+	}
+}
+
+// WithPlanTimeout bounds how long each of an execution's calls to Plan may take,
+// including when replanning. Planning which exceeds it fails, while executing the plan
+// may take as long as it needs. Defaults to 0, which means no timeout.
+func WithPlanTimeout(planTimeout time.Duration) ExecutionOption {
+	return func(o *executionOptions) {
+		o.planTimeout = planTimeout
+	}
+}
+
+// WithMinReplanInterval sets the minimum time between an execution's plans. A replan
+// requested sooner is deferred until the interval has elapsed, unless it was requested
+// because an obstacle was detected. Defaults to 0, which means replans are never deferred.
+func WithMinReplanInterval(minReplanInterval time.Duration) ExecutionOption {
+	return func(o *executionOptions) {
+		o.minReplanInterval = minReplanInterval
+	}
+}
+
 // StartExecution creates a new execution from a state.
+// Returns an error if the component already has an active execution which
+// the new execution doesn't have a higher priority than.
 func StartExecution[R any](
 	ctx context.Context,
 	s *State,
 	componentName resource.Name,
 	req R,
 	plannerExecutorConstructor PlannerExecutorConstructor[R],
+	opts ...ExecutionOption,
 ) (motion.ExecutionID, error) {
+	ids, err := StartExecutions(ctx, s, []ExecutionRequest[R]{{
+		ComponentName:              componentName,
+		Req:                        req,
+		PlannerExecutorConstructor: plannerExecutorConstructor,
+		Options:                    opts,
+	}})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return ids[componentName], nil
+}
+
+// ExecutionRequest describes one of the executions to start with StartExecutions.
+type ExecutionRequest[R any] struct {
+	ComponentName              resource.Name
+	Req                        R
+	PlannerExecutorConstructor PlannerExecutorConstructor[R]
+	Options                    []ExecutionOption
+}
+
+// StartExecutions creates a new execution from a state for each of the requests,
+// e.g. to move multiple bases together.
+// Either all of the executions are started or, if any of them conflict with an active
+// execution or fail to plan, none of them are & no active execution is preempted.
+// The components are reserved while their executions are planned, so a concurrent call for
+// any of them is rejected, while calls for other components aren't blocked by the planning.
+// Returns the ExecutionID of each component's execution.
+func StartExecutions[R any](
+	ctx context.Context,
+	s *State,
+	reqs []ExecutionRequest[R],
+) (map[resource.Name]motion.ExecutionID, error) {
 	if s == nil {
-		return uuid.Nil, errors.New("state is nil")
+		return nil, errors.New("state is nil")
+	}
+	if s.readOnly {
+		return nil, errors.New("state is read only")
 	}
 
-	if err := s.ValidateNoActiveExecutionID(componentName); err != nil {
-		return uuid.Nil, err
+	// validate all requests & reserve their components before stopping or starting anything
+	options := make([]executionOptions, len(reqs))
+	names := make([]resource.Name, len(reqs))
+	for i, r := range reqs {
+		for _, opt := range r.Options {
+			opt(&options[i])
+		}
+		names[i] = r.ComponentName
 	}
+	preempted, err := s.reserveComponents(names, options)
+	if err != nil {
+		return nil, err
+	}
+	defer s.unreserveComponents(names)
 
-	// the state being cancelled should cause all executions derived from that state to also be cancelled
-	cancelCtx, cancelFunc := context.WithCancel(s.cancelCtx)
-	e := execution[R]{
-		id:                         uuid.New(),
-		state:                      s,
-		cancelCtx:                  cancelCtx,
-		cancelFunc:                 cancelFunc,
-		waitGroup:                  &sync.WaitGroup{},
-		logger:                     s.logger,
-		req:                        req,
-		componentName:              componentName,
-		plannerExecutorConstructor: plannerExecutorConstructor,
+	if err := s.acquireExecutions(len(reqs), preempted); err != nil {
+		return nil, err
 	}
 
-	if err := e.start(ctx); err != nil {
-		return uuid.Nil, err
+	// plan all executions before starting any
+	executions := make([]*execution[R], 0, len(reqs))
+	plans := make([]planWithExecutor, 0, len(reqs))
+	for i, r := range reqs {
+		// the state being cancelled should cause all executions derived from that state to also be cancelled
+		cancelCtx, cancelFunc := context.WithCancelCause(s.cancelCtx)
+		e := &execution[R]{
+			id:                         s.newID(),
+			state:                      s,
+			cancelCtx:                  cancelCtx,
+			cancelFunc:                 cancelFunc,
+			waitGroup:                  &sync.WaitGroup{},
+			logger:                     s.logger,
+			req:                        r.Req,
+			componentName:              r.ComponentName,
+			priority:                   options[i].priority,
+			label:                      options[i].label,
+			planTimeout:                options[i].planTimeout,
+			minReplanInterval:          options[i].minReplanInterval,
+			replanTrigger:              make(chan motion.ReplanReason, 1),
+			slot:                       &executionSlot{},
+			plannerExecutorConstructor: r.PlannerExecutorConstructor,
+		}
+
+		pwe, err := e.newPlanWithExecutor(ctx, nil, 0)
+		if err != nil {
+			cancelFunc(nil)
+			for _, planned := range executions {
+				planned.cancelFunc(nil)
+			}
+			s.releaseExecutions(len(reqs))
+			return nil, err
+		}
+		executions = append(executions, e)
+		plans = append(plans, pwe)
+	}
+
+	// only preempt once all executions have planned, so they are certain to start
+	for _, es := range preempted {
+		s.logger.CDebugf(ctx, "execution %s for component %s preempted by higher priority execution", es.id, es.componentName)
+		es.stopWithCause(errPreempted)
 	}
 
-	return e.id, nil
+	ids := make(map[resource.Name]motion.ExecutionID, len(executions))
+	for i, e := range executions {
+		e.start(ctx, plans[i])
+		ids[e.componentName] = e.id
+	}
+	return ids, nil
 }
 
 // Stop stops all executions within the State.
 func (s *State) Stop() {
-	s.cancelFunc()
+	s.cancelFunc(ErrStateStopped)
 	s.waitGroup.Wait()
+	if s.statusChanges != nil {
+		// stopped after the executions so that their final statuses are delivered
+		s.statusChanges.stop()
+	}
 }
 
 // StopExecutionByResource stops the active execution with a given resource name in the State.
 func (s *State) StopExecutionByResource(componentName resource.Name) error {
-	// Read lock held to get the execution
+	e, err := s.lastExecution(componentName)
+	if err != nil {
+		return err
+	}
+
+	// lock released while waiting for the execution to stop as the execution stopping requires writing to the state
+	// which must take a lock
+	e.stop()
+	return nil
+}
+
+// TriggerReplan cancels the executor of the active execution with a given resource name in the State,
+// causing the execution to replan with the given reason rather than stop. The execution remains active.
+// If the execution isn't executing a plan, e.g. as it is planning, the replan happens once it starts
+// executing the next plan. Returns a NotFound error if the resource has no active execution.
+func (s *State) TriggerReplan(componentName resource.Name, replanReason motion.ReplanReason) error {
+	e, err := s.activeExecution(componentName)
+	if err != nil {
+		return err
+	}
+	select {
+	case e.replanTrigger <- replanReason:
+	default:
+		// a replan has already been triggered & not yet handled
+	}
+	return nil
+}
+
+// StopExecutionByResourceWithTimeout stops the active execution with a given resource name in the State,
+// waiting at most timeout for it to stop. If it doesn't stop in time an error wrapping ErrStopTimeout is
+// returned, though the execution remains cancelled.
+func (s *State) StopExecutionByResourceWithTimeout(componentName resource.Name, timeout time.Duration) error {
+	e, err := s.lastExecution(componentName)
+	if err != nil {
+		return err
+	}
+
+	// lock released while waiting for the execution to stop as the execution stopping requires writing to the state
+	// which must take a lock
+	return e.stopWithTimeout(timeout)
+}
+
+// lastExecution returns the most recent execution of a component.
+func (s *State) lastExecution(componentName resource.Name) (stateExecution, error) {
 	s.mu.RLock()
+	defer s.mu.RUnlock()
 	componentExectionState, exists := s.componentStateByComponent[componentName]
 
 	// return error if component name is not in StateMap
 	if !exists {
-		s.mu.RUnlock()
-		return resource.NewNotFoundError(componentName)
+		return stateExecution{}, resource.NewNotFoundError(componentName)
 	}
 
 	e, exists := componentExectionState.executionsByID[componentExectionState.lastExecutionID()]
 	if !exists {
-		s.mu.RUnlock()
-		return resource.NewNotFoundError(componentName)
+		return stateExecution{}, resource.NewNotFoundError(componentName)
+	}
+	return e, nil
+}
+
+// StopAllExecutions stops the active executions of all components in the State.
+// Unlike Stop, the State can still be used to start new executions afterwards &
+// the plan histories of the stopped executions are kept.
+func (s *State) StopAllExecutions() {
+	// Read lock held to get the active executions
+	s.mu.RLock()
+	active := []stateExecution{}
+	for _, cs := range s.componentStateByComponent {
+		if e, ok := cs.activeExecution(); ok {
+			active = append(active, e)
+		}
 	}
 	s.mu.RUnlock()
 
-	// lock released while waiting for the execution to stop as the execution stopping requires writing to the state
+	// lock released while waiting for the executions to stop as the executions stopping requires writing to the state
 	// which must take a lock
-	e.stop()
-	return nil
+	for _, e := range active {
+		e.stop()
+	}
 }
 
 // PlanHistory returns the plans with statuses of the resource
@@ -426,6 +1028,8 @@ func (s *State) StopExecutionByResource(componentName resource.Name) error {
 // If LastPlanOnly is provided then only the last plan is returned for the execution
 // with the ExecutionID if it is provided, or the last execution
 // for that component otherwise.
+// If the State was created with WithMaxPlansPerExecution or WithMaxStatusHistoryPerPlan
+// the returned history may be truncated.
 func (s *State) PlanHistory(req motion.PlanHistoryReq) ([]motion.PlanWithStatus, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -460,6 +1064,55 @@ func (s *State) PlanHistory(req motion.PlanHistoryReq) ([]motion.PlanWithStatus,
 	return renderableHistory(cs.lastExecution().history), nil
 }
 
+// LatestStatus returns the most recent status of the most recent plan of the
+// component's most recent execution. Unlike PlanHistory it doesn't copy the
+// execution's history, making it suitable for polling.
+func (s *State) LatestStatus(name resource.Name) (motion.PlanStatusWithID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cs, exists := s.componentStateByComponent[name]
+	if !exists {
+		return motion.PlanStatusWithID{}, resource.NewNotFoundError(name)
+	}
+
+	e := cs.lastExecution()
+	if len(e.history) == 0 || len(e.history[0].StatusHistory) == 0 {
+		return motion.PlanStatusWithID{}, resource.NewNotFoundError(name)
+	}
+
+	return motion.PlanStatusWithID{
+		ExecutionID:   e.id,
+		ComponentName: e.componentName,
+		PlanID:        e.history[0].Plan.ID,
+		Status:        e.history[0].StatusHistory[0],
+		Label:         e.label,
+	}, nil
+}
+
+// RawPlan returns the motionplan.Plan of a plan of one of the component's executions as
+// it was planned, without the conversion PlanHistory applies to make plans renderable,
+// e.g. for debugging a failed execution.
+func (s *State) RawPlan(name resource.Name, executionID motion.ExecutionID, planID motion.PlanID) (motionplan.Plan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cs, exists := s.componentStateByComponent[name]
+	if !exists {
+		return nil, resource.NewNotFoundError(name)
+	}
+
+	e, exists := cs.executionsByID[executionID]
+	if !exists {
+		return nil, fmt.Errorf("execution %s not found for component %s", executionID, name)
+	}
+
+	for _, pws := range e.history {
+		if pws.Plan.ID == planID {
+			return pws.Plan.Plan, nil
+		}
+	}
+	return nil, fmt.Errorf("plan %s not found in execution %s", planID, executionID)
+}
+
 // visualHistory returns the history struct that has had its plans Offset by.
 func renderableHistory(history []motion.PlanWithStatus) []motion.PlanWithStatus {
 	newHistory := make([]motion.PlanWithStatus, len(history))
@@ -470,10 +1123,74 @@ func renderableHistory(history []motion.PlanWithStatus) []motion.PlanWithStatus
 	return newHistory
 }
 
+// ActiveExecutionCount returns the number of components whose last execution is in a non terminal state.
+func (s *State) ActiveExecutionCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, cs := range s.componentStateByComponent {
+		if _, ok := cs.activeExecution(); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// ComponentsWithActivePlans returns the names of the components whose last execution is in a
+// non terminal state, ordered by name.
+func (s *State) ComponentsWithActivePlans() []resource.Name {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := []resource.Name{}
+	for name, cs := range s.componentStateByComponent {
+		if _, ok := cs.activeExecution(); ok {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b resource.Name) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	return names
+}
+
+// StuckExecutions returns the statuses of the in progress plans which have been in progress
+// for longer than the State's stuck threshold, ordered by component name.
+// Stuck executions are only reported, not stopped.
+func (s *State) StuckExecutions() []motion.PlanStatusWithID {
+	statuses := []motion.PlanStatusWithID{}
+	if s.stuckThreshold == 0 {
+		return statuses
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	componentNames := maps.Keys(s.componentStateByComponent)
+	slices.SortFunc(componentNames, func(a, b resource.Name) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	for _, name := range componentNames {
+		e := s.componentStateByComponent[name].lastExecution()
+		status := e.history[0].StatusHistory[0]
+		if status.State != motion.PlanStateInProgress || time.Since(status.Timestamp) <= s.stuckThreshold {
+			continue
+		}
+		statuses = append(statuses, motion.PlanStatusWithID{
+			ExecutionID:   e.id,
+			ComponentName: e.componentName,
+			PlanID:        e.history[0].Plan.ID,
+			Status:        status,
+			Label:         e.label,
+		})
+	}
+	return statuses
+}
+
 // ListPlanStatuses returns the status of plans created by MoveOnGlobe requests
 // that are executing OR are part of an execution which changed it state
 // within the a 24HR TTL OR until the robot reinitializes.
 // If OnlyActivePlans is provided, only returns plans which are in non terminal states.
+// If Label is provided, only returns plans of executions with that label.
+// If Since or Until are provided, only returns plans whose most recent status is within them.
 func (s *State) ListPlanStatuses(req motion.ListPlanStatusesReq) ([]motion.PlanStatusWithID, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -486,14 +1203,17 @@ func (s *State) ListPlanStatuses(req motion.ListPlanStatusesReq) ([]motion.PlanS
 
 	if req.OnlyActivePlans {
 		for _, name := range componentNames {
-			if e, err := s.activeExecution(name); err == nil {
-				statuses = append(statuses, motion.PlanStatusWithID{
-					ExecutionID:   e.id,
-					ComponentName: e.componentName,
-					PlanID:        e.history[0].Plan.ID,
-					Status:        e.history[0].StatusHistory[0],
-				})
+			e, err := s.activeExecution(name)
+			if err != nil || (req.Label != "" && req.Label != e.label) || !req.Includes(e.history[0].StatusHistory[0]) {
+				continue
 			}
+			statuses = append(statuses, motion.PlanStatusWithID{
+				ExecutionID:   e.id,
+				ComponentName: e.componentName,
+				PlanID:        e.history[0].Plan.ID,
+				Status:        e.history[0].StatusHistory[0],
+				Label:         e.label,
+			})
 		}
 		return statuses, nil
 	}
@@ -508,12 +1228,19 @@ func (s *State) ListPlanStatuses(req motion.ListPlanStatusesReq) ([]motion.PlanS
 			if !exists {
 				return nil, errors.New("state is corrupted")
 			}
+			if req.Label != "" && req.Label != e.label {
+				continue
+			}
 			for _, pws := range e.history {
+				if !req.Includes(pws.StatusHistory[0]) {
+					continue
+				}
 				statuses = append(statuses, motion.PlanStatusWithID{
 					ExecutionID:   e.id,
 					ComponentName: e.componentName,
 					PlanID:        pws.Plan.ID,
 					Status:        pws.StatusHistory[0],
+					Label:         e.label,
 				})
 			}
 		}
@@ -526,11 +1253,88 @@ func (s *State) ListPlanStatuses(req motion.ListPlanStatusesReq) ([]motion.PlanS
 // Execution for the resource name within the State.
 func (s *State) ValidateNoActiveExecutionID(name resource.Name) error {
 	if es, err := s.activeExecution(name); err == nil {
-		return fmt.Errorf("there is already an active executionID: %s", es.id)
+		return fmt.Errorf("%w: %s", ErrActiveExecution, es.id)
+	}
+	return nil
+}
+
+// reserveComponents reserves the components for executions with the given options, returning
+// the active executions they preempt. Returns an error, without reserving any of them, if a
+// component is requested more than once, is already reserved or has an active execution with
+// at least the priority of its new execution.
+func (s *State) reserveComponents(names []resource.Name, options []executionOptions) ([]stateExecution, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	preempted := []stateExecution{}
+	for i, name := range names {
+		for _, other := range names[:i] {
+			if other == name {
+				return nil, fmt.Errorf("multiple executions requested for component %s", name)
+			}
+		}
+		if _, starting := s.startingComponents[name]; starting {
+			return nil, fmt.Errorf("%w: another execution is being started for component %s", ErrActiveExecution, name)
+		}
+		cs, exists := s.componentStateByComponent[name]
+		if !exists {
+			continue
+		}
+		if es, active := cs.activeExecution(); active {
+			if options[i].priority <= es.priority {
+				return nil, fmt.Errorf("%w: %s", ErrActiveExecution, es.id)
+			}
+			preempted = append(preempted, es)
+		}
+	}
+	for _, name := range names {
+		s.startingComponents[name] = struct{}{}
+	}
+	return preempted, nil
+}
+
+// unreserveComponents releases components reserved by reserveComponents.
+func (s *State) unreserveComponents(names []resource.Name) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		delete(s.startingComponents, name)
+	}
+}
+
+// acquireExecutions reserves n of the State's concurrent executions, returning
+// ErrTooManyExecutions if there aren't that many available once the executions
+// which are going to be preempted release theirs.
+func (s *State) acquireExecutions(n int, preempted []stateExecution) error {
+	s.executionsMu.Lock()
+	defer s.executionsMu.Unlock()
+	available := s.maxExecutions - s.numExecutions
+	for _, es := range preempted {
+		if es.slot != nil && !es.slot.released {
+			available++
+		}
 	}
+	if s.maxExecutions > 0 && n > available {
+		return ErrTooManyExecutions
+	}
+	s.numExecutions += n
 	return nil
 }
 
+// releaseExecutions releases n executions reserved by acquireExecutions which weren't started.
+func (s *State) releaseExecutions(n int) {
+	s.executionsMu.Lock()
+	defer s.executionsMu.Unlock()
+	s.numExecutions -= n
+}
+
+// releaseExecutionSlot releases the execution reserved by acquireExecutions for a started execution.
+func (s *State) releaseExecutionSlot(slot *executionSlot) {
+	s.executionsMu.Lock()
+	defer s.executionsMu.Unlock()
+	slot.released = true
+	s.numExecutions--
+}
+
 func (s *State) updateStateNewExecution(newE stateExecution) {
 	cs, exists := s.componentStateByComponent[newE.componentName]
 
@@ -569,9 +1373,10 @@ func (s *State) updateStateNewPlan(newPlan planMsg) {
 	execution := s.componentStateByComponent[newPlan.plan.ComponentName].executionsByID[newPlan.plan.ExecutionID]
 	pws := []motion.PlanWithStatus{{Plan: newPlan.plan, StatusHistory: []motion.PlanStatus{newPlan.planStatus}}}
 	// prepend  to executions.history so that lower indices are newer
-	execution.history = append(pws, execution.history...)
+	execution.history = truncateHistory(append(pws, execution.history...), s.maxPlansPerExecution)
 
 	s.componentStateByComponent[newPlan.plan.ComponentName].executionsByID[newPlan.plan.ExecutionID] = execution
+	s.statusChanged(newPlan.plan.ID, execution, newPlan.planStatus)
 }
 
 func (s *State) updateStateStatusUpdate(update stateUpdateMsg) {
@@ -597,13 +1402,105 @@ func (s *State) updateStateStatusUpdate(update stateUpdateMsg) {
 		s.logger.Error(err.Error())
 		return
 	}
-	lastPlanWithStatus.StatusHistory = append([]motion.PlanStatus{update.planStatus}, lastPlanWithStatus.StatusHistory...)
+	lastPlanWithStatus.StatusHistory = truncateHistory(
+		append([]motion.PlanStatus{update.planStatus}, lastPlanWithStatus.StatusHistory...),
+		s.maxStatusHistoryPerPlan,
+	)
 	// write updated last plan back to history
 	execution.history[0] = lastPlanWithStatus
 	// write the execution with the new history to the component execution state copy
 	componentExecutions.executionsByID[update.executionID] = execution
 	// write the component execution state copy back to the state
 	s.componentStateByComponent[update.componentName] = componentExecutions
+	s.statusChanged(update.planID, execution, update.planStatus)
+}
+
+// statusChanged queues the status change of a plan to be passed to the status change callback, if there is one.
+// It is called with the lock held, so must not block.
+func (s *State) statusChanged(planID motion.PlanID, e stateExecution, status motion.PlanStatus) {
+	if s.statusChanges == nil {
+		return
+	}
+	s.statusChanges.push(motion.PlanStatusWithID{
+		PlanID:        planID,
+		ComponentName: e.componentName,
+		ExecutionID:   e.id,
+		Status:        status,
+		Label:         e.label,
+	})
+}
+
+// statusChangeNotifier calls a callback with plan status changes from its own goroutine.
+// Changes are queued without bound so that pushing a change never blocks the State.
+type statusChangeNotifier struct {
+	onStatusChange func(motion.PlanStatusWithID)
+	notify         chan struct{}
+	done           chan struct{}
+	stopped        chan struct{}
+	stopOnce       sync.Once
+	// mu protects pending
+	mu      sync.Mutex
+	pending []motion.PlanStatusWithID
+}
+
+func newStatusChangeNotifier(onStatusChange func(motion.PlanStatusWithID)) *statusChangeNotifier {
+	return &statusChangeNotifier{
+		onStatusChange: onStatusChange,
+		notify:         make(chan struct{}, 1),
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+}
+
+func (n *statusChangeNotifier) start() {
+	utils.PanicCapturingGo(func() {
+		defer close(n.stopped)
+		for {
+			select {
+			case <-n.done:
+				// deliver any changes which were queued before stopping
+				n.deliver()
+				return
+			case <-n.notify:
+				n.deliver()
+			}
+		}
+	})
+}
+
+func (n *statusChangeNotifier) push(status motion.PlanStatusWithID) {
+	n.mu.Lock()
+	n.pending = append(n.pending, status)
+	n.mu.Unlock()
+	select {
+	case n.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (n *statusChangeNotifier) deliver() {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	n.mu.Unlock()
+	for _, status := range pending {
+		n.onStatusChange(status)
+	}
+}
+
+// stop waits for all queued changes to be delivered. It is idempotent.
+func (n *statusChangeNotifier) stop() {
+	n.stopOnce.Do(func() { close(n.done) })
+	<-n.stopped
+}
+
+// truncateHistory drops the oldest entries of a newest first history once it is longer than maxLen,
+// always keeping the oldest entry. maxLen of 0 means unbounded.
+func truncateHistory[T any](history []T, maxLen int) []T {
+	if maxLen == 0 || len(history) <= maxLen {
+		return history
+	}
+	return append(history[:maxLen-1:maxLen-1], history[len(history)-1])
 }
 
 func (s *State) activeExecution(name resource.Name) (stateExecution, error) {
@@ -611,12 +1508,9 @@ func (s *State) activeExecution(name resource.Name) (stateExecution, error) {
 	defer s.mu.RUnlock()
 
 	if cs, exists := s.componentStateByComponent[name]; exists {
-		es := cs.lastExecution()
-
-		if _, exists := motion.TerminalStateSet[es.history[0].StatusHistory[0].State]; exists {
-			return stateExecution{}, resource.NewNotFoundError(name)
+		if es, ok := cs.activeExecution(); ok {
+			return es, nil
 		}
-		return es, nil
 	}
 	return stateExecution{}, resource.NewNotFoundError(name)
 }
@@ -2,12 +2,18 @@ package state_test
 
 import (
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
+	geo "github.com/kellydunn/golang-geo"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/base"
@@ -30,6 +36,7 @@ var (
 type testPlannerExecutor struct {
 	planFunc          func(context.Context) (motionplan.Plan, error)
 	executeFunc       func(context.Context, motionplan.Plan) (state.ExecuteResponse, error)
+	stopFunc          func() error
 	anchorGeoPoseFunc func() *spatialmath.GeoPose
 }
 
@@ -49,6 +56,14 @@ func (tpe *testPlannerExecutor) Execute(ctx context.Context, plan motionplan.Pla
 	return state.ExecuteResponse{}, nil
 }
 
+// by default Stop does nothing.
+func (tpe *testPlannerExecutor) Stop() error {
+	if tpe.stopFunc != nil {
+		return tpe.stopFunc()
+	}
+	return nil
+}
+
 func (tpe *testPlannerExecutor) AnchorGeoPose() *spatialmath.GeoPose {
 	if tpe.anchorGeoPoseFunc != nil {
 		return tpe.anchorGeoPoseFunc()
@@ -91,116 +106,1551 @@ func TestState(t *testing.T) {
 		}, nil
 	}
 
-	replanPlanConstructor := func(
-		ctx context.Context,
-		req motion.MoveOnGlobeReq,
-		seedplan motionplan.Plan,
-		replanCount int,
-	) (state.PlannerExecutor, error) {
-		return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
-			if err := ctx.Err(); err != nil {
-				return state.ExecuteResponse{}, err
-			}
-			return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
-		}}, nil
-	}
+	replanPlanConstructor := func(
+		ctx context.Context,
+		req motion.MoveOnGlobeReq,
+		seedplan motionplan.Plan,
+		replanCount int,
+	) (state.PlannerExecutor, error) {
+		return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+			if err := ctx.Err(); err != nil {
+				return state.ExecuteResponse{}, err
+			}
+			return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+		}}, nil
+	}
+
+	failedExecutionPlanConstructor := func(
+		ctx context.Context,
+		_ motion.MoveOnGlobeReq,
+		_ motionplan.Plan,
+		_ int,
+	) (state.PlannerExecutor, error) {
+		return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+			if err := ctx.Err(); err != nil {
+				return state.ExecuteResponse{}, err
+			}
+			return state.ExecuteResponse{}, errors.New("execution failed")
+		}}, nil
+	}
+
+	//nolint:unparam
+	failedPlanningPlanConstructor := func(
+		ctx context.Context,
+		_ motion.MoveOnGlobeReq,
+		_ motionplan.Plan,
+		_ int,
+	) (state.PlannerExecutor, error) {
+		return &testPlannerExecutor{
+			planFunc: func(context.Context) (motionplan.Plan, error) {
+				return nil, errors.New("planning failed")
+			},
+			executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				t.Fatal("should not be called as planning failed") //nolint:revive
+
+				if err := ctx.Err(); err != nil {
+					return state.ExecuteResponse{}, err
+				}
+				return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+			},
+		}, nil
+	}
+
+	failedReplanningPlanConstructor := func(
+		ctx context.Context,
+		_ motion.MoveOnGlobeReq,
+		_ motionplan.Plan,
+		replanCount int,
+	) (state.PlannerExecutor, error) {
+		// first replan fails during planning
+		if replanCount == 1 {
+			return &testPlannerExecutor{
+				planFunc: func(ctx context.Context) (motionplan.Plan, error) {
+					return nil, errors.New("planning failed")
+				},
+				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+					if err := ctx.Err(); err != nil {
+						return state.ExecuteResponse{}, err
+					}
+					return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+				},
+			}, nil
+		}
+		// first plan generates a plan but execution triggers a replan
+		return &testPlannerExecutor{
+			executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				if err := ctx.Err(); err != nil {
+					return state.ExecuteResponse{}, err
+				}
+				return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+			},
+		}, nil
+	}
+
+	emptyReq := motion.MoveOnGlobeReq{ComponentName: myBase}
+	ctx := context.Background()
+
+	t.Run("returns error if TTL is not set", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(0, 0, logger)
+		test.That(t, err, test.ShouldBeError, errors.New("TTL can't be unset"))
+		test.That(t, s, test.ShouldBeNil)
+	})
+
+	t.Run("returns error if TTLCheckInterval is not set", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(2, 0, logger)
+		test.That(t, err, test.ShouldBeError, errors.New("TTLCheckInterval can't be unset"))
+		test.That(t, s, test.ShouldBeNil)
+	})
+
+	t.Run("returns error if Logger is nil", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(2, 1, nil)
+		test.That(t, err, test.ShouldBeError, errors.New("Logger can't be nil"))
+		test.That(t, s, test.ShouldBeNil)
+	})
+
+	t.Run("returns error if TTL < TTLCheckInterval", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(1, 2, logger)
+		test.That(t, err, test.ShouldBeError, errors.New("TTL can't be lower than the TTLCheckInterval"))
+		test.That(t, s, test.ShouldBeNil)
+	})
+
+	t.Run("returns error if id generator is nil", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithIDGenerator(nil))
+		test.That(t, err, test.ShouldBeError, errors.New("id generator can't be nil"))
+		test.That(t, s, test.ShouldBeNil)
+	})
+
+	t.Run("execution & plan ids come from the id generator", func(t *testing.T) {
+		t.Parallel()
+		var (
+			mu    sync.Mutex
+			count uint32
+		)
+		idN := func(n uint32) uuid.UUID {
+			var id uuid.UUID
+			binary.BigEndian.PutUint32(id[12:], n)
+			return id
+		}
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithIDGenerator(func() uuid.UUID {
+			mu.Lock()
+			defer mu.Unlock()
+			count++
+			return idN(count)
+		}))
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		// replans once, then executes until cancelled
+		replanOnceConstructor := func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			if replanCount > 0 {
+				return executionWaitingForCtxCancelledPlanConstructor(ctx, req, seedplan, replanCount)
+			}
+			return replanPlanConstructor(ctx, req, seedplan, replanCount)
+		}
+
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, replanOnceConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, executionID, test.ShouldResemble, idN(1))
+
+		timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+		defer timeoutFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && len(pws) == 2
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		// newest plan first
+		test.That(t, pws[0].Plan.ID, test.ShouldResemble, idN(3))
+		test.That(t, pws[0].Plan.ExecutionID, test.ShouldResemble, idN(1))
+		test.That(t, pws[1].Plan.ID, test.ShouldResemble, idN(2))
+		test.That(t, pws[1].Plan.ExecutionID, test.ShouldResemble, idN(1))
+
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+	})
+
+	t.Run("returns error if max executions is negative", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithMaxExecutions(-1))
+		test.That(t, err, test.ShouldBeError, errors.New("max executions can't be negative"))
+		test.That(t, s, test.ShouldBeNil)
+	})
+
+	t.Run("returns ErrTooManyExecutions when the max executions are active", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithMaxExecutions(2))
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		base1 := base.Named("base1")
+		base2 := base.Named("base2")
+		base3 := base.Named("base3")
+		for _, name := range []resource.Name{base1, base2} {
+			req := motion.MoveOnGlobeReq{ComponentName: name}
+			_, err = state.StartExecution(ctx, s, name, req, executionWaitingForCtxCancelledPlanConstructor)
+			test.That(t, err, test.ShouldBeNil)
+		}
+
+		req := motion.MoveOnGlobeReq{ComponentName: base3}
+		id, err := state.StartExecution(ctx, s, base3, req, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeError, state.ErrTooManyExecutions)
+		test.That(t, id, test.ShouldResemble, uuid.Nil)
+
+		// once an execution stops another can be started
+		test.That(t, s.StopExecutionByResource(base1), test.ShouldBeNil)
+
+		// failing to plan doesn't hold on to an execution
+		_, err = state.StartExecution(ctx, s, base3, req, failedPlanningPlanConstructor)
+		test.That(t, err, test.ShouldBeError, errors.New("planning failed"))
+
+		_, err = state.StartExecution(ctx, s, base3, req, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("a higher priority execution preempts the active execution", func(t *testing.T) {
+		t.Parallel()
+		// the preempted execution's slot is available to the preempting one
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithMaxExecutions(1))
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		executionID1, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq,
+			executionWaitingForCtxCancelledPlanConstructor, state.WithPriority(1))
+		test.That(t, err, test.ShouldBeNil)
+
+		// equal & lower priorities are rejected
+		for _, priority := range []int{0, 1} {
+			id, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq,
+				executionWaitingForCtxCancelledPlanConstructor, state.WithPriority(priority))
+			test.That(t, err, test.ShouldBeError, fmt.Errorf("there is already an active executionID: %s", executionID1))
+			test.That(t, errors.Is(err, state.ErrActiveExecution), test.ShouldBeTrue)
+			test.That(t, id, test.ShouldResemble, uuid.Nil)
+		}
+
+		// a higher priority execution which fails to plan doesn't preempt
+		id, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq,
+			failedPlanningPlanConstructor, state.WithPriority(2))
+		test.That(t, err, test.ShouldBeError, errors.New("planning failed"))
+		test.That(t, id, test.ShouldResemble, uuid.Nil)
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID1)
+
+		executionID2, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq,
+			executionWaitingForCtxCancelledPlanConstructor, state.WithPriority(2))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, executionID2, test.ShouldNotResemble, executionID1)
+
+		// the preempted execution's plan is stopped with the preempted reason
+		ph, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase, ExecutionID: executionID1})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ph), test.ShouldEqual, 1)
+		test.That(t, ph[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+		test.That(t, ph[0].StatusHistory[0].Reason, test.ShouldNotBeNil)
+		test.That(t, *ph[0].StatusHistory[0].Reason, test.ShouldEqual, state.PreemptedReason)
+
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID2)
+
+		// stopping an execution directly gives the stopped reason
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+		ph, err = s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ph[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+		test.That(t, *ph[0].StatusHistory[0].Reason, test.ShouldEqual, state.ErrExecutionStopped.Error())
+	})
+
+	t.Run("LatestStatus returns the most recent status of the most recent plan", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		_, err = s.LatestStatus(myBase)
+		test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(myBase))
+
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		ps, err := s.LatestStatus(myBase)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps.ExecutionID, test.ShouldResemble, executionID)
+		test.That(t, ps.ComponentName, test.ShouldResemble, myBase)
+		test.That(t, ps.Status.State, test.ShouldEqual, motion.PlanStateInProgress)
+
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+		ps2, err := s.LatestStatus(myBase)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps2.PlanID, test.ShouldResemble, ps.PlanID)
+		test.That(t, ps2.Status.State, test.ShouldEqual, motion.PlanStateStopped)
+
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase, LastPlanOnly: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps2.Status, test.ShouldResemble, pws[0].StatusHistory[0])
+	})
+
+	t.Run("executions can be labeled & plan statuses filtered by label", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		base1 := base.Named("base1")
+		base2 := base.Named("base2")
+		req1 := motion.MoveOnGlobeReq{ComponentName: base1}
+		executionID1, err := state.StartExecution(ctx, s, base1, req1,
+			executionWaitingForCtxCancelledPlanConstructor, state.WithLabel("delivery-run-42"))
+		test.That(t, err, test.ShouldBeNil)
+		req2 := motion.MoveOnGlobeReq{ComponentName: base2}
+		executionID2, err := state.StartExecution(ctx, s, base2, req2, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 2)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID1)
+		test.That(t, ps[0].Label, test.ShouldEqual, "delivery-run-42")
+		test.That(t, ps[1].ExecutionID, test.ShouldResemble, executionID2)
+		test.That(t, ps[1].Label, test.ShouldBeEmpty)
+
+		for _, onlyActive := range []bool{false, true} {
+			ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{Label: "delivery-run-42", OnlyActivePlans: onlyActive})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(ps), test.ShouldEqual, 1)
+			test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID1)
+
+			ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{Label: "some other label", OnlyActivePlans: onlyActive})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ps, test.ShouldBeEmpty)
+		}
+
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: base1})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pws[0].Plan.Label, test.ShouldEqual, "delivery-run-42")
+	})
+
+	t.Run("StartExecutions starts all executions or none of them", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		base1 := base.Named("base1")
+		base2 := base.Named("base2")
+		base3 := base.Named("base3")
+		type executionRequest = state.ExecutionRequest[motion.MoveOnGlobeReq]
+		batch := func(constructors ...state.PlannerExecutorConstructor[motion.MoveOnGlobeReq]) []executionRequest {
+			reqs := []executionRequest{}
+			for i, name := range []resource.Name{base1, base2, base3}[:len(constructors)] {
+				reqs = append(reqs, executionRequest{
+					ComponentName:              name,
+					Req:                        motion.MoveOnGlobeReq{ComponentName: name},
+					PlannerExecutorConstructor: constructors[i],
+				})
+			}
+			return reqs
+		}
+
+		// base3 has an active execution
+		req3 := motion.MoveOnGlobeReq{ComponentName: base3}
+		executionID3, err := state.StartExecution(ctx, s, base3, req3, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		// the whole batch is rejected
+		ids, err := state.StartExecutions(ctx, s, batch(
+			executionWaitingForCtxCancelledPlanConstructor,
+			executionWaitingForCtxCancelledPlanConstructor,
+			executionWaitingForCtxCancelledPlanConstructor,
+		))
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("there is already an active executionID: %s", executionID3))
+		test.That(t, ids, test.ShouldBeNil)
+		for _, name := range []resource.Name{base1, base2} {
+			_, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: name})
+			test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(name))
+		}
+
+		// the whole batch is rejected if any execution fails to plan
+		ids, err = state.StartExecutions(ctx, s, batch(
+			executionWaitingForCtxCancelledPlanConstructor,
+			failedPlanningPlanConstructor,
+		))
+		test.That(t, err, test.ShouldBeError, errors.New("planning failed"))
+		test.That(t, ids, test.ShouldBeNil)
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+
+		// a batch which would preempt an active execution doesn't if any execution fails to plan
+		preemptingReqs := batch(
+			executionWaitingForCtxCancelledPlanConstructor,
+			failedPlanningPlanConstructor,
+			executionWaitingForCtxCancelledPlanConstructor,
+		)
+		preemptingReqs[2].Options = []state.ExecutionOption{state.WithPriority(1)}
+		ids, err = state.StartExecutions(ctx, s, preemptingReqs)
+		test.That(t, err, test.ShouldBeError, errors.New("planning failed"))
+		test.That(t, ids, test.ShouldBeNil)
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID3)
+		test.That(t, ps[0].Status.State, test.ShouldEqual, motion.PlanStateInProgress)
+
+		// a batch can't have multiple executions for the same component
+		reqs := batch(executionWaitingForCtxCancelledPlanConstructor, executionWaitingForCtxCancelledPlanConstructor)
+		reqs[1].ComponentName = base1
+		_, err = state.StartExecutions(ctx, s, reqs)
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("multiple executions requested for component %s", base1))
+
+		// otherwise all executions are started
+		ids, err = state.StartExecutions(ctx, s, batch(
+			executionWaitingForCtxCancelledPlanConstructor,
+			executionWaitingForCtxCancelledPlanConstructor,
+		))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ids), test.ShouldEqual, 2)
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 3)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, ids[base1])
+		test.That(t, ps[1].ExecutionID, test.ShouldResemble, ids[base2])
+		test.That(t, ps[2].ExecutionID, test.ShouldResemble, executionID3)
+	})
+
+	t.Run("planning an execution only blocks starting executions for the same component", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		base1 := base.Named("base1")
+		base2 := base.Named("base2")
+		planning := make(chan struct{})
+		finishPlanning := make(chan struct{})
+		blockedPlanningPlanConstructor := func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			pe, err := executionWaitingForCtxCancelledPlanConstructor(ctx, req, seedplan, replanCount)
+			if err != nil {
+				return nil, err
+			}
+			pe.(*testPlannerExecutor).planFunc = func(ctx context.Context) (motionplan.Plan, error) {
+				close(planning)
+				<-finishPlanning
+				return nil, nil
+			}
+			return pe, nil
+		}
+
+		var (
+			executionID1 motion.ExecutionID
+			err1         error
+		)
+		started := make(chan struct{})
+		go func() {
+			defer close(started)
+			req1 := motion.MoveOnGlobeReq{ComponentName: base1}
+			executionID1, err1 = state.StartExecution(ctx, s, base1, req1, blockedPlanningPlanConstructor)
+		}()
+		<-planning
+
+		// another execution for the component being planned is rejected
+		req1 := motion.MoveOnGlobeReq{ComponentName: base1}
+		_, err = state.StartExecution(ctx, s, base1, req1, executionWaitingForCtxCancelledPlanConstructor, state.WithPriority(1))
+		test.That(t, errors.Is(err, state.ErrActiveExecution), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "another execution is being started for component")
+
+		// while other components' executions start without waiting for the planning
+		req2 := motion.MoveOnGlobeReq{ComponentName: base2}
+		executionID2, err := state.StartExecution(ctx, s, base2, req2, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		close(finishPlanning)
+		<-started
+		test.That(t, err1, test.ShouldBeNil)
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 2)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID1)
+		test.That(t, ps[1].ExecutionID, test.ShouldResemble, executionID2)
+
+		// once started, the component can be preempted as usual
+		_, err = state.StartExecution(ctx, s, base1, req1, executionWaitingForCtxCancelledPlanConstructor, state.WithPriority(1))
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("StopAllExecutions stops all active executions & leaves the state usable", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		// stopping with no executions is a no-op
+		s.StopAllExecutions()
+
+		base1 := base.Named("base1")
+		base2 := base.Named("base2")
+		base3 := base.Named("base3")
+		for _, name := range []resource.Name{base1, base2} {
+			req := motion.MoveOnGlobeReq{ComponentName: name}
+			_, err := state.StartExecution(ctx, s, name, req, executionWaitingForCtxCancelledPlanConstructor)
+			test.That(t, err, test.ShouldBeNil)
+		}
+		// base3's execution has already succeeded
+		req3 := motion.MoveOnGlobeReq{ComponentName: base3}
+		_, err = state.StartExecution(ctx, s, base3, req3, successPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+		defer timeoutFn()
+		_, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: base3})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateSucceeded
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+
+		s.StopAllExecutions()
+		// idempotent
+		s.StopAllExecutions()
+
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps, test.ShouldBeEmpty)
+
+		for _, name := range []resource.Name{base1, base2} {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: name})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(pws), test.ShouldEqual, 1)
+			test.That(t, len(pws[0].StatusHistory), test.ShouldEqual, 2)
+			test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+			test.That(t, pws[0].StatusHistory[1].State, test.ShouldEqual, motion.PlanStateInProgress)
+		}
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: base3})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(pws[0].StatusHistory), test.ShouldEqual, 2)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+
+		// new executions can still be started
+		req1 := motion.MoveOnGlobeReq{ComponentName: base1}
+		executionID, err := state.StartExecution(ctx, s, base1, req1, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID)
+	})
+
+	t.Run("WithPlanTimeout bounds planning but not execution", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		planTimeout := time.Millisecond * 50
+		slowPlan := func(ctx context.Context) (motionplan.Plan, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		// a slow planner fails with a timeout
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{planFunc: slowPlan}, nil
+		}, state.WithPlanTimeout(planTimeout))
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("planning timed out after %s: %w", planTimeout, context.DeadlineExceeded))
+
+		// a fast planner with an execution longer than the plan timeout succeeds
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+					select {
+					case <-ctx.Done():
+						return state.ExecuteResponse{}, ctx.Err()
+					case <-time.After(planTimeout * 4):
+						return state.ExecuteResponse{}, nil
+					}
+				},
+			}, nil
+		}, state.WithPlanTimeout(planTimeout))
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+		defer timeoutFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State != motion.PlanStateInProgress
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+
+		// a slow replan fails the execution with a timeout reason
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			if replanCount > 0 {
+				return &testPlannerExecutor{planFunc: slowPlan}, nil
+			}
+			return replanPlanConstructor(ctx, req, seedplan, replanCount)
+		}, state.WithPlanTimeout(planTimeout))
+		test.That(t, err, test.ShouldBeNil)
+
+		pws, succ = pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State != motion.PlanStateInProgress
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
+		test.That(t, *pws[0].StatusHistory[0].Reason, test.ShouldContainSubstring, "planning timed out")
+	})
+
+	t.Run("RawPlan returns the plan as it was planned", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		_, err = s.RawPlan(myBase, uuid.New(), uuid.New())
+		test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(myBase))
+
+		step := motionplan.PathStep{
+			myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 10})),
+		}
+		rawPlan := motionplan.NewSimplePlan([]motionplan.PathStep{step}, nil)
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				planFunc: func(context.Context) (motionplan.Plan, error) {
+					return rawPlan, nil
+				},
+				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+					<-ctx.Done()
+					return state.ExecuteResponse{}, ctx.Err()
+				},
+				anchorGeoPoseFunc: func() *spatialmath.GeoPose {
+					return spatialmath.NewGeoPose(geo.NewPoint(40, -73), 0)
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		// PlanHistory returns plans anchored at the AnchorGeoPose
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pws[0].Plan.Plan, test.ShouldNotResemble, rawPlan)
+
+		plan, err := s.RawPlan(myBase, executionID, pws[0].Plan.ID)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, plan, test.ShouldResemble, rawPlan)
+
+		planID := uuid.New()
+		_, err = s.RawPlan(myBase, executionID, planID)
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("plan %s not found in execution %s", planID, executionID))
+
+		otherExecutionID := uuid.New()
+		_, err = s.RawPlan(myBase, otherExecutionID, pws[0].Plan.ID)
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("execution %s not found for component %s", otherExecutionID, myBase))
+	})
+
+	t.Run("PlanHistory results of anchored executions can be exported as GeoJSON", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		rawPlan := motionplan.NewSimplePlan([]motionplan.PathStep{
+			{myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewZeroPose())},
+			{myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{Y: 1000}))},
+		}, nil)
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				planFunc: func(context.Context) (motionplan.Plan, error) {
+					return rawPlan, nil
+				},
+				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+					<-ctx.Done()
+					return state.ExecuteResponse{}, ctx.Err()
+				},
+				anchorGeoPoseFunc: func() *spatialmath.GeoPose {
+					return spatialmath.NewGeoPose(geo.NewPoint(40, -73), 0)
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		b, err := motion.PlanToGeoJSON(pws[0].Plan)
+		test.That(t, err, test.ShouldBeNil)
+
+		var feature struct {
+			Geometry struct {
+				Coordinates [][]float64
+			}
+		}
+		test.That(t, json.Unmarshal(b, &feature), test.ShouldBeNil)
+		test.That(t, len(feature.Geometry.Coordinates), test.ShouldEqual, 2)
+		// the first step is at the anchor & the second is north of it
+		test.That(t, feature.Geometry.Coordinates[0][0], test.ShouldAlmostEqual, -73)
+		test.That(t, feature.Geometry.Coordinates[0][1], test.ShouldAlmostEqual, 40)
+		test.That(t, feature.Geometry.Coordinates[1][0], test.ShouldAlmostEqual, -73)
+		test.That(t, feature.Geometry.Coordinates[1][1], test.ShouldBeGreaterThan, 40)
+	})
+
+	t.Run("executions without an AnchorGeoPose keep their plan steps unchanged", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		step := motionplan.PathStep{
+			myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 10})),
+		}
+		rawPlan := motionplan.NewSimplePlan([]motionplan.PathStep{step}, nil)
+		req := motion.MoveOnMapReq{ComponentName: myBase}
+		_, err = state.StartExecution(ctx, s, req.ComponentName, req, func(
+			ctx context.Context,
+			req motion.MoveOnMapReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				planFunc: func(context.Context) (motionplan.Plan, error) {
+					return rawPlan, nil
+				},
+				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+					<-ctx.Done()
+					return state.ExecuteResponse{}, ctx.Err()
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pws[0].Plan.AnchorGeoPose, test.ShouldBeNil)
+		test.That(t, pws[0].Plan.Plan, test.ShouldResemble, rawPlan)
+	})
+
+	t.Run("replans are deferred until the minimum replan interval elapses", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		minReplanInterval := time.Millisecond * 50
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, replanPlanConstructor,
+			state.WithMinReplanInterval(minReplanInterval))
+		test.That(t, err, test.ShouldBeNil)
+
+		elapsed := time.Millisecond * 300
+		time.Sleep(elapsed)
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(pws), test.ShouldBeGreaterThan, 1)
+		test.That(t, len(pws), test.ShouldBeLessThanOrEqualTo, int(elapsed/minReplanInterval)+1)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+	})
+
+	t.Run("replans due to a detected obstacle are not deferred", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				if replanCount > 2 {
+					return state.ExecuteResponse{}, nil
+				}
+				return state.ExecuteResponse{
+					Replan:       true,
+					ReplanReason: replanReason,
+					ReplanKind:   motion.ReplanReasonObstacleDetected,
+				}, nil
+			}}, nil
+		}, state.WithMinReplanInterval(time.Hour))
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State != motion.PlanStateInProgress
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, len(pws), test.ShouldEqual, 4)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+	})
+
+	t.Run("replans record the reason Execute returned", func(t *testing.T) {
+		t.Parallel()
+		for _, tc := range []struct {
+			name           string
+			reason         string
+			expectedReason string
+		}{
+			{name: "with a reason", reason: replanReason, expectedReason: replanReason},
+			{name: "without a reason", reason: "", expectedReason: "replan triggered without providing a reason"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				s, err := state.NewState(ttl, ttlCheckInterval, logger)
+				test.That(t, err, test.ShouldBeNil)
+				defer s.Stop()
+
+				_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+					ctx context.Context,
+					req motion.MoveOnGlobeReq,
+					seedplan motionplan.Plan,
+					replanCount int,
+				) (state.PlannerExecutor, error) {
+					return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+						if replanCount > 0 {
+							return state.ExecuteResponse{}, nil
+						}
+						return state.ExecuteResponse{Replan: true, ReplanReason: tc.reason}, nil
+					}}, nil
+				})
+				test.That(t, err, test.ShouldBeNil)
+
+				timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+				defer cancelFn()
+				pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+					pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+					return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateSucceeded
+				})
+				test.That(t, succ, test.ShouldBeTrue)
+				test.That(t, len(pws), test.ShouldEqual, 2)
+				test.That(t, pws[1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
+				test.That(t, *pws[1].StatusHistory[0].Reason, test.ShouldEqual, tc.expectedReason)
+			})
+		}
+	})
+
+	t.Run("stopping an execution stops an executor which ignores its context", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		stopped := make(chan struct{})
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				executeFunc: func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+					<-stopped
+					return state.ExecuteResponse{}, context.Canceled
+				},
+				stopFunc: func() error {
+					close(stopped)
+					return nil
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+	})
+
+	t.Run("StopExecutionByResourceWithTimeout returns an error if the execution doesn't stop in time", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		err = s.StopExecutionByResourceWithTimeout(myBase, time.Millisecond)
+		test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(myBase))
+
+		release := make(chan struct{})
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				executeFunc: func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+					// ignores ctx
+					<-release
+					return state.ExecuteResponse{}, context.Canceled
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		err = s.StopExecutionByResourceWithTimeout(myBase, time.Millisecond*50)
+		test.That(t, errors.Is(err, state.ErrStopTimeout), test.ShouldBeTrue)
+
+		// once the executor returns the execution is stopped
+		close(release)
+		test.That(t, s.StopExecutionByResourceWithTimeout(myBase, time.Second*5), test.ShouldBeNil)
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+		s.Stop()
+	})
+
+	t.Run("the status change callback is called with every status change", func(t *testing.T) {
+		t.Parallel()
+		var (
+			mu       sync.Mutex
+			changes  []motion.PlanStatusWithID
+			listErrs []error
+			s        *state.State
+		)
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithStatusChangeCallback(func(ps motion.PlanStatusWithID) {
+			// the callback is able to call the state without deadlocking
+			_, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{})
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, ps)
+			listErrs = append(listErrs, err)
+		}))
+		test.That(t, err, test.ShouldBeNil)
+
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				if replanCount > 0 {
+					return state.ExecuteResponse{}, nil
+				}
+				return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+			}}, nil
+		}, state.WithLabel("label"))
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateSucceeded
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		// Stop waits for all changes to be delivered
+		s.Stop()
+
+		mu.Lock()
+		defer mu.Unlock()
+		test.That(t, len(changes), test.ShouldEqual, 4)
+		expected := []struct {
+			planID motion.PlanID
+			state  motion.PlanState
+		}{
+			{pws[1].Plan.ID, motion.PlanStateInProgress},
+			{pws[1].Plan.ID, motion.PlanStateFailed},
+			{pws[0].Plan.ID, motion.PlanStateInProgress},
+			{pws[0].Plan.ID, motion.PlanStateSucceeded},
+		}
+		for i, change := range changes {
+			test.That(t, change.PlanID, test.ShouldEqual, expected[i].planID)
+			test.That(t, change.Status.State, test.ShouldEqual, expected[i].state)
+			test.That(t, change.ExecutionID, test.ShouldEqual, executionID)
+			test.That(t, change.ComponentName, test.ShouldResemble, myBase)
+			test.That(t, change.Label, test.ShouldEqual, "label")
+			test.That(t, listErrs[i], test.ShouldBeNil)
+		}
+	})
+
+	t.Run("executions in progress for longer than the stuck threshold are reported as stuck", func(t *testing.T) {
+		t.Parallel()
+		_, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithStuckThreshold(-1))
+		test.That(t, err, test.ShouldBeError, errors.New("stuck threshold can't be negative"))
+
+		stuckThreshold := time.Millisecond * 100
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithStuckThreshold(stuckThreshold))
+		test.That(t, err, test.ShouldBeNil)
+
+		release := make(chan struct{})
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				executeFunc: func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+					// never returns until released, ignoring ctx
+					<-release
+					return state.ExecuteResponse{}, context.Canceled
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, s.StuckExecutions(), test.ShouldBeEmpty)
+
+		time.Sleep(stuckThreshold * 2)
+		stuck := s.StuckExecutions()
+		test.That(t, len(stuck), test.ShouldEqual, 1)
+		test.That(t, stuck[0].ExecutionID, test.ShouldEqual, executionID)
+		test.That(t, stuck[0].Status.State, test.ShouldEqual, motion.PlanStateInProgress)
+
+		// stuck executions are not stopped
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+
+		close(release)
+		s.Stop()
+		test.That(t, s.StuckExecutions(), test.ShouldBeEmpty)
+	})
+
+	t.Run("stopping the state gives the stopped plans the state stopped reason", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		s.Stop()
+
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+		test.That(t, *pws[0].StatusHistory[0].Reason, test.ShouldEqual, state.ErrStateStopped.Error())
+	})
+
+	t.Run("ActiveExecutionCount & ComponentsWithActivePlans only count active executions", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 0)
+		test.That(t, s.ComponentsWithActivePlans(), test.ShouldBeEmpty)
+
+		base1 := base.Named("base1")
+		base2 := base.Named("base2")
+		base3 := base.Named("base3")
+		for _, name := range []resource.Name{base2, base1} {
+			_, err = state.StartExecution(ctx, s, name, motion.MoveOnGlobeReq{ComponentName: name},
+				executionWaitingForCtxCancelledPlanConstructor)
+			test.That(t, err, test.ShouldBeNil)
+		}
+		_, err = state.StartExecution(ctx, s, base3, motion.MoveOnGlobeReq{ComponentName: base3}, failedExecutionPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		_, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: base3})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateFailed
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 2)
+		test.That(t, s.ComponentsWithActivePlans(), test.ShouldResemble, []resource.Name{base1, base2})
+
+		test.That(t, s.StopExecutionByResource(base1), test.ShouldBeNil)
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 1)
+		test.That(t, s.ComponentsWithActivePlans(), test.ShouldResemble, []resource.Name{base2})
+	})
+
+	t.Run("ActiveExecutionCount & ComponentsWithActivePlans are safe to call concurrently with executions", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			name := base.Named(fmt.Sprintf("base%d", i))
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					_, err := state.StartExecution(ctx, s, name, motion.MoveOnGlobeReq{ComponentName: name},
+						executionWaitingForCtxCancelledPlanConstructor)
+					test.That(t, err, test.ShouldBeNil)
+					test.That(t, s.StopExecutionByResource(name), test.ShouldBeNil)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					test.That(t, s.ActiveExecutionCount(), test.ShouldBeBetweenOrEqual, 0, 5)
+					test.That(t, len(s.ComponentsWithActivePlans()), test.ShouldBeBetweenOrEqual, 0, 5)
+				}
+			}()
+		}
+		wg.Wait()
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 0)
+	})
+
+	t.Run("a plan without steps succeeds without being executed", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		var executeCount atomic.Int32
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				planFunc: func(context.Context) (motionplan.Plan, error) {
+					return motionplan.NewSimplePlan(nil, nil), nil
+				},
+				executeFunc: func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+					executeCount.Add(1)
+					return state.ExecuteResponse{}, nil
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State != motion.PlanStateInProgress
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, len(pws), test.ShouldEqual, 1)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+		test.That(t, executeCount.Load(), test.ShouldEqual, 0)
+	})
+
+	t.Run("plan history is capped at the max plans per execution", func(t *testing.T) {
+		t.Parallel()
+		for _, maxLen := range []int{-1, 1} {
+			_, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithMaxPlansPerExecution(maxLen))
+			test.That(t, err, test.ShouldBeError, errors.New("max plans per execution must be 0 or at least 2"))
+			_, err = state.NewState(ttl, ttlCheckInterval, logger, state.WithMaxStatusHistoryPerPlan(maxLen))
+			test.That(t, err, test.ShouldBeError, errors.New("max status history per plan must be 0 or at least 2"))
+		}
+
+		s, err := state.NewState(ttl, ttlCheckInterval, logger, state.WithMaxPlansPerExecution(3), state.WithMaxStatusHistoryPerPlan(2))
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		replans := 10
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{
+				planFunc: func(context.Context) (motionplan.Plan, error) {
+					// each plan's step records the replan count which created it
+					step := motionplan.PathStep{
+						myBase.ShortName(): referenceframe.NewPoseInFrame(
+							referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: float64(replanCount)})),
+					}
+					return motionplan.NewSimplePlan([]motionplan.PathStep{step}, nil), nil
+				},
+				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+					if replanCount == replans {
+						return state.ExecuteResponse{}, nil
+					}
+					return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+				},
+			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateSucceeded
+		})
+		test.That(t, succ, test.ShouldBeTrue)
 
-	failedExecutionPlanConstructor := func(
-		ctx context.Context,
-		_ motion.MoveOnGlobeReq,
-		_ motionplan.Plan,
-		_ int,
-	) (state.PlannerExecutor, error) {
-		return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
-			if err := ctx.Err(); err != nil {
-				return state.ExecuteResponse{}, err
-			}
-			return state.ExecuteResponse{}, errors.New("execution failed")
-		}}, nil
-	}
+		// the most recent plans & the first plan are kept
+		test.That(t, len(pws), test.ShouldEqual, 3)
+		for i, replanCount := range []int{replans, replans - 1, 0} {
+			test.That(t, pws[i].Plan.Plan.Path()[0][myBase.ShortName()].Pose().Point().X, test.ShouldEqual, float64(replanCount))
+			test.That(t, len(pws[i].StatusHistory), test.ShouldEqual, 2)
+			test.That(t, pws[i].StatusHistory[1].State, test.ShouldEqual, motion.PlanStateInProgress)
+		}
+	})
 
-	//nolint:unparam
-	failedPlanningPlanConstructor := func(
-		ctx context.Context,
-		_ motion.MoveOnGlobeReq,
-		_ motionplan.Plan,
-		_ int,
-	) (state.PlannerExecutor, error) {
-		return &testPlannerExecutor{
-			planFunc: func(context.Context) (motionplan.Plan, error) {
-				return nil, errors.New("planning failed")
-			},
-			executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
-				t.Fatal("should not be called as planning failed") //nolint:revive
+	t.Run("a state loaded from a snapshot can be queried like the state it was exported from", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
 
-				if err := ctx.Err(); err != nil {
-					return state.ExecuteResponse{}, err
+		executionID1, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				if replanCount > 0 {
+					return state.ExecuteResponse{}, nil
 				}
 				return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
-			},
-		}, nil
-	}
+			}}, nil
+		}, state.WithLabel("label"))
+		test.That(t, err, test.ShouldBeNil)
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		_, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateSucceeded
+		})
+		test.That(t, succ, test.ShouldBeTrue)
 
-	failedReplanningPlanConstructor := func(
-		ctx context.Context,
-		_ motion.MoveOnGlobeReq,
-		_ motionplan.Plan,
-		replanCount int,
-	) (state.PlannerExecutor, error) {
-		// first replan fails during planning
-		if replanCount == 1 {
+		executionID2, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+
+		snapshot := []motion.PlanWithStatus{}
+		for _, executionID := range []motion.ExecutionID{executionID1, executionID2} {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase, ExecutionID: executionID})
+			test.That(t, err, test.ShouldBeNil)
+			snapshot = append(snapshot, pws...)
+		}
+		test.That(t, len(snapshot), test.ShouldEqual, 3)
+
+		loaded, err := state.LoadFromSnapshot(snapshot)
+		test.That(t, err, test.ShouldBeNil)
+		defer loaded.Stop()
+
+		for _, req := range []motion.PlanHistoryReq{
+			{ComponentName: myBase},
+			{ComponentName: myBase, ExecutionID: executionID1},
+			{ComponentName: myBase, LastPlanOnly: true},
+		} {
+			expected, err := s.PlanHistory(req)
+			test.That(t, err, test.ShouldBeNil)
+			pws, err := loaded.PlanHistory(req)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, pws, test.ShouldResemble, expected)
+		}
+		for _, req := range []motion.ListPlanStatusesReq{{}, {OnlyActivePlans: true}, {Label: "label"}} {
+			expected, err := s.ListPlanStatuses(req)
+			test.That(t, err, test.ShouldBeNil)
+			ps, err := loaded.ListPlanStatuses(req)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ps, test.ShouldResemble, expected)
+		}
+
+		_, err = state.StartExecution(ctx, loaded, emptyReq.ComponentName, emptyReq, successPlanConstructor)
+		test.That(t, err, test.ShouldBeError, errors.New("state is read only"))
+	})
+
+	t.Run("LoadFromSnapshot returns an error if a plan isn't in a terminal state", func(t *testing.T) {
+		t.Parallel()
+		planID := uuid.New()
+		_, err := state.LoadFromSnapshot([]motion.PlanWithStatus{{
+			Plan:          motion.PlanWithMetadata{ID: planID, ComponentName: myBase, ExecutionID: uuid.New()},
+			StatusHistory: []motion.PlanStatus{{State: motion.PlanStateInProgress, Timestamp: time.Now()}},
+		}})
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("plan %s is not in a terminal state", planID))
+	})
+
+	t.Run("a replan whose path & trajectory are misaligned fails the plan with the lengths of both", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		step := motionplan.PathStep{myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewZeroPose())}
+		input := map[string][]referenceframe.Input{myBase.ShortName(): {{Value: 0}}}
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
 			return &testPlannerExecutor{
-				planFunc: func(ctx context.Context) (motionplan.Plan, error) {
-					return nil, errors.New("planning failed")
+				planFunc: func(context.Context) (motionplan.Plan, error) {
+					if replanCount == 0 {
+						return motionplan.NewSimplePlan(motionplan.Path{step, step}, motionplan.Trajectory{input, input}), nil
+					}
+					return motionplan.NewSimplePlan(motionplan.Path{step, step}, motionplan.Trajectory{input}), nil
 				},
 				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
-					if err := ctx.Err(); err != nil {
-						return state.ExecuteResponse{}, err
-					}
 					return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
 				},
 			}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateFailed
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, len(pws), test.ShouldEqual, 1)
+		expectedReason := fmt.Sprintf(
+			"planner returned a misaligned plan for execution %s and component %s: path has 2 steps but trajectory has 1",
+			executionID, myBase)
+		test.That(t, *pws[0].StatusHistory[0].Reason, test.ShouldEqual, expectedReason)
+
+		// a misaligned first plan is returned by StartExecution
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{planFunc: func(context.Context) (motionplan.Plan, error) {
+				return motionplan.NewSimplePlan(motionplan.Path{step}, motionplan.Trajectory{input, input}), nil
+			}}, nil
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "path has 1 steps but trajectory has 2")
+	})
+
+	t.Run("concurrent operations on overlapping components keep the state consistent", func(t *testing.T) {
+		t.Parallel()
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		components := []resource.Name{myBase, base.Named("mybase2"), base.Named("mybase3")}
+		shortExecutionPlanConstructor := func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				select {
+				case <-ctx.Done():
+					return state.ExecuteResponse{}, ctx.Err()
+				case <-time.After(time.Millisecond):
+					return state.ExecuteResponse{}, nil
+				}
+			}}, nil
 		}
-		// first plan generates a plan but execution triggers a replan
-		return &testPlannerExecutor{
-			executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
-				if err := ctx.Err(); err != nil {
-					return state.ExecuteResponse{}, err
+
+		var (
+			violationsMu sync.Mutex
+			violations   []string
+			wg           sync.WaitGroup
+		)
+		violation := func(format string, args ...interface{}) {
+			violationsMu.Lock()
+			defer violationsMu.Unlock()
+			violations = append(violations, fmt.Sprintf(format, args...))
+		}
+		checkHistory := func(name resource.Name) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: name})
+			if err != nil {
+				return
+			}
+			if len(pws) == 0 {
+				violation("component %s has an execution without plans", name)
+			}
+			for _, p := range pws {
+				if len(p.StatusHistory) == 0 {
+					violation("plan %s has no status history", p.Plan.ID)
 				}
-				return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
-			},
-		}, nil
-	}
+			}
+		}
 
-	emptyReq := motion.MoveOnGlobeReq{ComponentName: myBase}
-	ctx := context.Background()
+		goroutines := 20
+		iterations := 50
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < iterations; i++ {
+					name := components[(g+i)%len(components)]
+					req := motion.MoveOnGlobeReq{ComponentName: name}
+					switch i % 4 {
+					case 0:
+						//nolint:errcheck
+						state.StartExecution(ctx, s, name, req, shortExecutionPlanConstructor)
+					case 1:
+						//nolint:errcheck
+						s.StopExecutionByResource(name)
+					case 2:
+						if _, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{}); err != nil {
+							violation("ListPlanStatuses returned an error: %s", err)
+						}
+					default:
+						checkHistory(name)
+					}
+				}
+			}(g)
+		}
 
-	t.Run("returns error if TTL is not set", func(t *testing.T) {
-		t.Parallel()
-		s, err := state.NewState(0, 0, logger)
-		test.That(t, err, test.ShouldBeError, errors.New("TTL can't be unset"))
-		test.That(t, s, test.ShouldBeNil)
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*10)
+		defer cancelFn()
+		select {
+		case <-done:
+		case <-timeoutCtx.Done():
+			t.Fatal("timed out waiting for concurrent operations, likely deadlocked")
+		}
+		test.That(t, violations, test.ShouldBeEmpty)
+
+		// every component has at most one active execution, which is its most recent one
+		for _, name := range components {
+			checkHistory(name)
+			test.That(t, s.StopExecutionByResource(name), test.ShouldBeNil)
+		}
+		test.That(t, violations, test.ShouldBeEmpty)
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps, test.ShouldBeEmpty)
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 0)
 	})
 
-	t.Run("returns error if TTLCheckInterval is not set", func(t *testing.T) {
+	t.Run("a panic in Execute fails the plan with the panic message", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(2, 0, logger)
-		test.That(t, err, test.ShouldBeError, errors.New("TTLCheckInterval can't be unset"))
-		test.That(t, s, test.ShouldBeNil)
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		_, err = state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, func(
+			ctx context.Context,
+			req motion.MoveOnGlobeReq,
+			seedplan motionplan.Plan,
+			replanCount int,
+		) (state.PlannerExecutor, error) {
+			return &testPlannerExecutor{executeFunc: func(context.Context, motionplan.Plan) (state.ExecuteResponse, error) {
+				panic("executor crashed")
+			}}, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && pws[0].StatusHistory[0].State == motion.PlanStateFailed
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, *pws[0].StatusHistory[0].Reason, test.ShouldEqual, "executor panicked: executor crashed")
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 0)
 	})
 
-	t.Run("returns error if Logger is nil", func(t *testing.T) {
+	t.Run("TriggerReplan replans the active execution without stopping it", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(2, 1, nil)
-		test.That(t, err, test.ShouldBeError, errors.New("Logger can't be nil"))
-		test.That(t, s, test.ShouldBeNil)
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		err = s.TriggerReplan(myBase, motion.ReplanReasonManual)
+		test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(myBase))
+
+		executionID, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, s.TriggerReplan(myBase, motion.ReplanReasonManual), test.ShouldBeNil)
+
+		timeoutCtx, cancelFn := context.WithTimeout(ctx, time.Second*5)
+		defer cancelFn()
+		pws, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+			pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+			return pws, err == nil && len(pws) == 2
+		})
+		test.That(t, succ, test.ShouldBeTrue)
+		test.That(t, pws[0].Plan.ExecutionID, test.ShouldEqual, executionID)
+		test.That(t, pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateInProgress)
+		test.That(t, pws[1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
+		test.That(t, *pws[1].StatusHistory[0].Reason, test.ShouldEqual, state.TriggeredReplanReason)
+		test.That(t, pws[1].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonManual)
+		test.That(t, s.ActiveExecutionCount(), test.ShouldEqual, 1)
+
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+		err = s.TriggerReplan(myBase, motion.ReplanReasonManual)
+		test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(myBase))
 	})
 
-	t.Run("returns error if TTL < TTLCheckInterval", func(t *testing.T) {
+	t.Run("ListPlanStatuses filters by the time of the most recent status", func(t *testing.T) {
 		t.Parallel()
-		s, err := state.NewState(1, 2, logger)
-		test.That(t, err, test.ShouldBeError, errors.New("TTL can't be lower than the TTLCheckInterval"))
-		test.That(t, s, test.ShouldBeNil)
+		s, err := state.NewState(ttl, ttlCheckInterval, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer s.Stop()
+
+		executionID1, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, s.StopExecutionByResource(myBase), test.ShouldBeNil)
+
+		betweenExecutions := time.Now()
+		executionID2, err := state.StartExecution(ctx, s, emptyReq.ComponentName, emptyReq, executionWaitingForCtxCancelledPlanConstructor)
+		test.That(t, err, test.ShouldBeNil)
+
+		ps, err := s.ListPlanStatuses(motion.ListPlanStatusesReq{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 2)
+
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{Since: betweenExecutions})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID2)
+
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{Until: betweenExecutions})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID1)
+
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true, Until: betweenExecutions})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps, test.ShouldBeEmpty)
+
+		ps, err = s.ListPlanStatuses(motion.ListPlanStatusesReq{OnlyActivePlans: true, Since: betweenExecutions, Until: time.Now()})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(ps), test.ShouldEqual, 1)
+		test.That(t, ps[0].ExecutionID, test.ShouldResemble, executionID2)
 	})
 
 	t.Run("creating & stopping a state with no intermediary calls", func(t *testing.T) {
@@ -414,7 +1864,7 @@ func TestState(t *testing.T) {
 		test.That(t, ps5[0].PlanID, test.ShouldNotEqual, uuid.Nil)
 		// status now shows that the plan is stopped
 		test.That(t, ps5[0].Status.State, test.ShouldEqual, motion.PlanStateStopped)
-		test.That(t, ps5[0].Status.Reason, test.ShouldBeNil)
+		test.That(t, *ps5[0].Status.Reason, test.ShouldEqual, state.ErrExecutionStopped.Error())
 		test.That(t, ps5[0].Status.Timestamp.After(preStop), test.ShouldBeTrue)
 
 		// Returns no results if active plans are requested & there are no active plans
@@ -435,7 +1885,7 @@ func TestState(t *testing.T) {
 		test.That(t, pws2[0].StatusHistory[1], test.ShouldResemble, pws[0].StatusHistory[0])
 		// most recent PlanStatus is now that it is stopped
 		test.That(t, pws2[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
-		test.That(t, pws2[0].StatusHistory[0].Reason, test.ShouldEqual, nil)
+		test.That(t, *pws2[0].StatusHistory[0].Reason, test.ShouldEqual, state.ErrExecutionStopped.Error())
 		test.That(t, planStatusTimestampsInOrder(pws2[0].StatusHistory), test.ShouldBeTrue)
 
 		preExecution2 := time.Now()
@@ -512,6 +1962,8 @@ func TestState(t *testing.T) {
 		test.That(t, resPWS.pws[1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
 		test.That(t, resPWS.pws[1].StatusHistory[0].Reason, test.ShouldNotBeNil)
 		test.That(t, *resPWS.pws[1].StatusHistory[0].Reason, test.ShouldResemble, replanReason)
+		// replan responses which don't categorize the reason are recorded as other
+		test.That(t, resPWS.pws[1].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonOther)
 		test.That(t, resPWS.pws[1].StatusHistory[0].Timestamp.After(execution2Replan1), test.ShouldBeTrue)
 		test.That(t, planStatusTimestampsInOrder(resPWS.pws[0].StatusHistory), test.ShouldBeTrue)
 		test.That(t, planStatusTimestampsInOrder(resPWS.pws[1].StatusHistory), test.ShouldBeTrue)
@@ -601,10 +2053,10 @@ func TestState(t *testing.T) {
 				},
 				executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
 					if replanCount == 0 {
-						return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+						return state.ExecuteResponse{Replan: true, ReplanReason: replanReason, ReplanKind: motion.ReplanReasonPositionDrift}, nil
 					}
 					if replanCount == 1 {
-						return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+						return state.ExecuteResponse{Replan: true, ReplanReason: replanReason, ReplanKind: motion.ReplanReasonPositionDrift}, nil
 					}
 					t.Log("shouldn't execute as first replanning fails")
 					t.FailNow()
@@ -636,12 +2088,15 @@ func TestState(t *testing.T) {
 		test.That(t, len(resPWS3.pws[1].StatusHistory), test.ShouldEqual, 2)
 		test.That(t, resPWS3.pws[1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
 		test.That(t, *resPWS3.pws[1].StatusHistory[0].Reason, test.ShouldResemble, replanReason)
+		test.That(t, resPWS3.pws[1].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonPositionDrift)
 		test.That(t, resPWS3.pws[1].StatusHistory[1].State, test.ShouldEqual, motion.PlanStateInProgress)
 		test.That(t, resPWS3.pws[1].StatusHistory[1].Reason, test.ShouldBeNil)
 		test.That(t, resPWS3.pws[1].StatusHistory[1].Timestamp.After(preExecution3), test.ShouldBeTrue)
 		test.That(t, len(resPWS3.pws[0].StatusHistory), test.ShouldEqual, 2)
 		test.That(t, resPWS3.pws[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
 		test.That(t, *resPWS3.pws[0].StatusHistory[0].Reason, test.ShouldResemble, replanFailReason.Error())
+		// the typed reason replanning was triggered for is kept when replanning fails
+		test.That(t, resPWS3.pws[0].StatusHistory[0].ReplanReason, test.ShouldEqual, motion.ReplanReasonPositionDrift)
 		test.That(t, resPWS3.pws[0].StatusHistory[1].State, test.ShouldEqual, motion.PlanStateInProgress)
 		test.That(t, resPWS3.pws[0].StatusHistory[1].Reason, test.ShouldBeNil)
 		test.That(t, len(resPWS3.pws[0].Plan.Path()), test.ShouldEqual, 2)
@@ -820,7 +2275,7 @@ func TestState(t *testing.T) {
 		test.That(t, ps1[1].ComponentName, test.ShouldResemble, req.ComponentName)
 		test.That(t, ps1[1].PlanID, test.ShouldNotEqual, uuid.Nil)
 		test.That(t, ps1[1].Status.State, test.ShouldEqual, motion.PlanStateStopped)
-		test.That(t, ps1[1].Status.Reason, test.ShouldBeNil)
+		test.That(t, *ps1[1].Status.Reason, test.ShouldEqual, state.ErrExecutionStopped.Error())
 		test.That(t, ps1[1].Status.Timestamp.After(preExecution), test.ShouldBeTrue)
 
 		// by default planHistory returns the most recent plan
@@ -1038,6 +2493,62 @@ type pwsRes struct {
 // pollUntil returns when either the ctx is cancelled or f returns success = true.
 // this is needed so the tests can wait until the state has been updated with the results
 // of the PlannerExecutor interface methods.
+func BenchmarkLatestStatus(b *testing.B) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(b)
+	myBase := base.Named("mybase")
+	s, err := state.NewState(ttl, ttlCheckInterval, logger)
+	test.That(b, err, test.ShouldBeNil)
+	defer s.Stop()
+
+	// replan a number of times so the execution has a long history to copy
+	numReplans := 100
+	req := motion.MoveOnGlobeReq{ComponentName: myBase}
+	_, err = state.StartExecution(ctx, s, myBase, req, func(
+		ctx context.Context,
+		req motion.MoveOnGlobeReq,
+		seedplan motionplan.Plan,
+		replanCount int,
+	) (state.PlannerExecutor, error) {
+		return &testPlannerExecutor{
+			executeFunc: func(ctx context.Context, plan motionplan.Plan) (state.ExecuteResponse, error) {
+				if replanCount < numReplans {
+					return state.ExecuteResponse{Replan: true, ReplanReason: replanReason}, nil
+				}
+				<-ctx.Done()
+				return state.ExecuteResponse{}, ctx.Err()
+			},
+		}, nil
+	})
+	test.That(b, err, test.ShouldBeNil)
+
+	timeoutCtx, timeoutFn := context.WithTimeout(ctx, time.Second*5)
+	defer timeoutFn()
+	_, succ := pollUntil(timeoutCtx, func() ([]motion.PlanWithStatus, bool) {
+		pws, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase})
+		return pws, err == nil && len(pws) == numReplans+1
+	})
+	test.That(b, succ, test.ShouldBeTrue)
+
+	b.Run("LatestStatus", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.LatestStatus(myBase); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("PlanHistory", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.PlanHistory(motion.PlanHistoryReq{ComponentName: myBase}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func pollUntil[T any](ctx context.Context, f func() (T, bool)) (T, bool) {
 	t, b := f()
 	for {
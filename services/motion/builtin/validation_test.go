@@ -16,10 +16,12 @@ import (
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/movementsensor"
 	_ "go.viam.com/rdk/components/register"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/services/slam"
 	"go.viam.com/rdk/services/vision"
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
 )
 
 func TestMoveCallInputs(t *testing.T) {
@@ -40,7 +42,28 @@ func TestMoveCallInputs(t *testing.T) {
 			}
 
 			executionID, err := ms.(*builtIn).MoveOnMap(context.Background(), req)
-			test.That(t, err, test.ShouldBeError, errors.New("Resource missing from dependencies. Resource: rdk:component:base/non existent base"))
+			test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(req.ComponentName))
+			test.That(t, executionID, test.ShouldResemble, uuid.Nil)
+		})
+
+		t.Run("Returns a not base error when the component is not a base", func(t *testing.T) {
+			t.Parallel()
+			_, ms := createMoveOnMapEnvironment(ctx, t, "pointcloud/octagonspace.pcd", 40, nil)
+			defer ms.Close(ctx)
+			cam := inject.NewCamera("test-camera")
+			ms.(*builtIn).mu.Lock()
+			ms.(*builtIn).components[cam.Name()] = cam
+			ms.(*builtIn).mu.Unlock()
+
+			req := motion.MoveOnMapReq{
+				ComponentName: cam.Name(),
+				Destination:   spatialmath.NewZeroPose(),
+				SlamName:      slam.Named("test_slam"),
+			}
+
+			executionID, err := ms.(*builtIn).MoveOnMap(context.Background(), req)
+			test.That(t, motion.IsNotBaseError(err), test.ShouldBeTrue)
+			test.That(t, err, test.ShouldBeError, motion.NewNotBaseError(cam.Name(), cam))
 			test.That(t, executionID, test.ShouldResemble, uuid.Nil)
 		})
 
@@ -71,7 +94,7 @@ func TestMoveCallInputs(t *testing.T) {
 			}
 
 			executionID, err := ms.(*builtIn).MoveOnMap(context.Background(), req)
-			test.That(t, err, test.ShouldBeError, errors.New("Resource missing from dependencies. Resource: rdk:service:slam/test_slam"))
+			test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(req.ComponentName))
 			test.That(t, executionID, test.ShouldResemble, uuid.Nil)
 		})
 
@@ -417,7 +440,27 @@ func TestMoveCallInputs(t *testing.T) {
 				Destination:        geo.NewPoint(0, 0),
 			}
 			executionID, err := ms.MoveOnGlobe(ctx, req)
-			test.That(t, err, test.ShouldBeError, errors.New("resource \"rdk:component:base/non existent base\" not found"))
+			test.That(t, err, test.ShouldBeError, resource.NewNotFoundError(req.ComponentName))
+			test.That(t, executionID, test.ShouldResemble, uuid.Nil)
+		})
+
+		t.Run("returns a not base error when the component is not a base", func(t *testing.T) {
+			t.Parallel()
+			injectedMovementSensor, _, _, ms := createMoveOnGlobeEnvironment(ctx, t, gpsPoint, nil, 5)
+			defer ms.Close(ctx)
+			cam := inject.NewCamera("test-camera")
+			ms.(*builtIn).mu.Lock()
+			ms.(*builtIn).components[cam.Name()] = cam
+			ms.(*builtIn).mu.Unlock()
+
+			req := motion.MoveOnGlobeReq{
+				ComponentName:      cam.Name(),
+				MovementSensorName: injectedMovementSensor.Name(),
+				Destination:        dst,
+			}
+			executionID, err := ms.MoveOnGlobe(ctx, req)
+			test.That(t, motion.IsNotBaseError(err), test.ShouldBeTrue)
+			test.That(t, err, test.ShouldBeError, motion.NewNotBaseError(cam.Name(), cam))
 			test.That(t, executionID, test.ShouldResemble, uuid.Nil)
 		})
 
@@ -847,4 +890,15 @@ func TestNewValidatedMotionCfg(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("PlanDeviationMM reaches the kinematics options unscaled", func(t *testing.T) {
+		for _, reqType := range []requestType{requestTypeMoveOnGlobe, requestTypeMoveOnMap} {
+			vmc, err := newValidatedMotionCfg(&motion.MotionConfiguration{PlanDeviationMM: 150}, reqType)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, vmc.planDeviationMM, test.ShouldEqual, 150.)
+			kbOpts := kbOptionsFromCfg(vmc, validatedExtra{})
+			test.That(t, kbOpts.PlanDeviationThresholdMM, test.ShouldEqual, 150.)
+			test.That(t, kbOpts.GoalRadiusMM, test.ShouldEqual, 150.)
+		}
+	})
 }
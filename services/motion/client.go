@@ -155,15 +155,11 @@ func (c *client) StopPlan(ctx context.Context, req StopPlanReq) error {
 }
 
 func (c *client) ListPlanStatuses(ctx context.Context, req ListPlanStatusesReq) ([]PlanStatusWithID, error) {
-	ext, err := vprotoutils.StructToStructPb(req.Extra)
+	protoReq, err := req.toProto(c.name)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.client.ListPlanStatuses(ctx, &pb.ListPlanStatusesRequest{
-		Name:            c.name,
-		OnlyActivePlans: req.OnlyActivePlans,
-		Extra:           ext,
-	})
+	resp, err := c.client.ListPlanStatuses(ctx, protoReq)
 	if err != nil {
 		return nil, err
 	}
@@ -336,6 +336,25 @@ func TestClient(t *testing.T) {
 			test.That(t, executionID, test.ShouldResemble, uuid.Nil)
 		})
 
+		t.Run("the motion configuration reaches the server in the same units", func(t *testing.T) {
+			planDeviationMM := 150.
+			injectMS.MoveOnGlobeFunc = func(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
+				test.That(t, req.MotionCfg.PlanDeviationMM, test.ShouldAlmostEqual, planDeviationMM)
+				test.That(t, req.MotionCfg.LinearMPerSec, test.ShouldAlmostEqual, 0.5)
+				return uuid.New(), nil
+			}
+
+			req := motion.MoveOnGlobeReq{
+				ComponentName:      baseName,
+				Destination:        globeDest,
+				Heading:            math.NaN(),
+				MovementSensorName: gpsName,
+				MotionCfg:          &motion.MotionConfiguration{PlanDeviationMM: planDeviationMM, LinearMPerSec: 0.5},
+			}
+			_, err := client.MoveOnGlobe(ctx, req)
+			test.That(t, err, test.ShouldBeNil)
+		})
+
 		t.Run("otherwise returns success with an executionID", func(t *testing.T) {
 			expectedExecutionID := uuid.New()
 			injectMS.MoveOnGlobeFunc = func(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
@@ -428,7 +447,7 @@ func TestClient(t *testing.T) {
 			status := motion.PlanStatus{State: motion.PlanStateInProgress, Timestamp: time.Now().UTC(), Reason: nil}
 
 			expectedResp := []motion.PlanStatusWithID{
-				{PlanID: planID, ComponentName: base.Named("mybase"), ExecutionID: executionID, Status: status},
+				{PlanID: planID, ComponentName: base.Named("mybase"), ExecutionID: executionID, Status: status, Label: "delivery-run-42"},
 			}
 
 			injectMS.ListPlanStatusesFunc = func(
@@ -457,7 +476,12 @@ func TestClient(t *testing.T) {
 			executionIDB := uuid.New()
 
 			reason := "failed reason"
-			statusB := motion.PlanStatus{State: motion.PlanStateInProgress, Timestamp: time.Now().UTC(), Reason: &reason}
+			statusB := motion.PlanStatus{
+				State:        motion.PlanStateFailed,
+				Timestamp:    time.Now().UTC(),
+				Reason:       &reason,
+				ReplanReason: motion.ReplanReasonObstacleDetected,
+			}
 
 			expectedResp := []motion.PlanStatusWithID{
 				{PlanID: planIDA, ComponentName: base.Named("mybase"), ExecutionID: executionIDA, Status: statusA},
@@ -477,6 +501,26 @@ func TestClient(t *testing.T) {
 			test.That(t, resp, test.ShouldResemble, expectedResp)
 		})
 
+		t.Run("sends the label filter", func(t *testing.T) {
+			var received motion.ListPlanStatusesReq
+			injectMS.ListPlanStatusesFunc = func(
+				ctx context.Context,
+				req motion.ListPlanStatusesReq,
+			) ([]motion.PlanStatusWithID, error) {
+				received = req
+				return []motion.PlanStatusWithID{}, nil
+			}
+
+			extra := map[string]interface{}{"foo": "bar"}
+			req := motion.ListPlanStatusesReq{Label: "delivery-run-42", Extra: extra}
+			_, err := client.ListPlanStatuses(ctx, req)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, received.Label, test.ShouldEqual, "delivery-run-42")
+			test.That(t, received.Extra["foo"], test.ShouldEqual, "bar")
+			// the caller's extra isn't modified
+			test.That(t, extra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+		})
+
 		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
@@ -515,10 +559,11 @@ func TestClient(t *testing.T) {
 				ComponentName: base.Named("mybase"),
 				ExecutionID:   executionID,
 				Plan:          motionplan.NewSimplePlan(steps, nil),
+				Label:         "delivery-run-42",
 			}
 			statusHistory := []motion.PlanStatus{
-				{motion.PlanStateFailed, timeB, &reason},
-				{motion.PlanStateInProgress, timeA, nil},
+				{State: motion.PlanStateFailed, Timestamp: timeB, Reason: &reason, ReplanReason: motion.ReplanReasonPositionDrift},
+				{State: motion.PlanStateInProgress, Timestamp: timeA},
 			}
 			expectedResp := []motion.PlanWithStatus{{Plan: plan, StatusHistory: statusHistory}}
 			injectMS.PlanHistoryFunc = func(ctx context.Context, req motion.PlanHistoryReq) ([]motion.PlanWithStatus, error) {
@@ -551,8 +596,8 @@ func TestClient(t *testing.T) {
 				Plan:          motionplan.NewSimplePlan(steps, nil),
 			}
 			statusHistoryA := []motion.PlanStatus{
-				{motion.PlanStateFailed, timeAB, &reason},
-				{motion.PlanStateInProgress, timeAA, nil},
+				{State: motion.PlanStateFailed, Timestamp: timeAB, Reason: &reason},
+				{State: motion.PlanStateInProgress, Timestamp: timeAA},
 			}
 
 			idB := uuid.New()
@@ -566,7 +611,7 @@ func TestClient(t *testing.T) {
 			}
 
 			statusHistoryB := []motion.PlanStatus{
-				{motion.PlanStateInProgress, timeBA, nil},
+				{State: motion.PlanStateInProgress, Timestamp: timeBA},
 			}
 
 			expectedResp := []motion.PlanWithStatus{
@@ -584,6 +629,33 @@ func TestClient(t *testing.T) {
 			planHistoriesEqual(t, resp, expectedResp)
 		})
 
+		t.Run("forwards LastPlanOnly & ExecutionID to the service", func(t *testing.T) {
+			steps := []motionplan.PathStep{{"mybase": zeroPoseInFrame}}
+			executionID := uuid.New()
+			expectedResp := []motion.PlanWithStatus{{
+				Plan: motion.PlanWithMetadata{
+					ID:            uuid.New(),
+					ComponentName: base.Named("mybase"),
+					ExecutionID:   executionID,
+					Plan:          motionplan.NewSimplePlan(steps, nil),
+				},
+				StatusHistory: []motion.PlanStatus{{State: motion.PlanStateInProgress, Timestamp: time.Now().UTC()}},
+			}}
+			var receivedReq motion.PlanHistoryReq
+			injectMS.PlanHistoryFunc = func(ctx context.Context, req motion.PlanHistoryReq) ([]motion.PlanWithStatus, error) {
+				receivedReq = req
+				return expectedResp, nil
+			}
+
+			req := motion.PlanHistoryReq{ComponentName: base.Named("mybase"), LastPlanOnly: true, ExecutionID: executionID}
+			resp, err := client.PlanHistory(ctx, req)
+			test.That(t, err, test.ShouldBeNil)
+			planHistoriesEqual(t, resp, expectedResp)
+			test.That(t, receivedReq.ComponentName, test.ShouldResemble, req.ComponentName)
+			test.That(t, receivedReq.LastPlanOnly, test.ShouldBeTrue)
+			test.That(t, receivedReq.ExecutionID, test.ShouldResemble, executionID)
+		})
+
 		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
@@ -598,5 +670,6 @@ func planHistoriesEqual(t *testing.T, resp, expectedResp []motion.PlanWithStatus
 		test.That(t, resp[i].StatusHistory, test.ShouldResemble, expectedResp[i].StatusHistory)
 		test.That(t, resp[i].Plan.ExecutionID, test.ShouldResemble, expectedResp[i].Plan.ExecutionID)
 		test.That(t, resp[i].Plan.ComponentName, test.ShouldResemble, expectedResp[i].Plan.ComponentName)
+		test.That(t, resp[i].Plan.Label, test.ShouldEqual, expectedResp[i].Plan.Label)
 	}
 }
@@ -1,6 +1,37 @@
 package motion
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
 
 // ErrGoalWithinPlanDeviation is an error describing when planning fails because there is nothing to be done.
 var ErrGoalWithinPlanDeviation = errors.New("no need to move, already within planDeviationMM")
+
+// ErrBaseAlreadyMoving is returned, without planning, by a move which sets RejectIfMovingExtraKey
+// when the component already has an active execution or reports that it is moving.
+var ErrBaseAlreadyMoving = errors.New("base already moving")
+
+// NewNotBaseError is used when the component requested to be moved exists but is not a base,
+// i.e. it does not implement Navigable.
+func NewNotBaseError(name resource.Name, component interface{}) error {
+	return &notBaseError{name: name, componentType: fmt.Sprintf("%T", component)}
+}
+
+// IsNotBaseError returns if the given error is any kind of not base error.
+func IsNotBaseError(err error) bool {
+	var errArt *notBaseError
+	return errors.As(err, &errArt)
+}
+
+type notBaseError struct {
+	name          resource.Name
+	componentType string
+}
+
+func (e *notBaseError) Error() string {
+	return fmt.Sprintf("cannot move component %q of type %s because it is not a Base", e.name, e.componentType)
+}
@@ -0,0 +1,67 @@
+package motion
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONLineString      `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONLineString struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+// PlanToGeoJSON returns the path of a plan anchored at a GeoPose, such as a MoveOnGlobe plan, as a
+// GeoJSON Feature with a LineString geometry, for visualizing in mapping tools.
+// Coordinates are [longitude, latitude] & the compass heading of each step, in degrees clockwise from
+// north, is returned in the Feature's "headings" property. The plan may be as returned by PlanHistory.
+// Returns an error if the plan has no AnchorGeoPose.
+func PlanToGeoJSON(plan PlanWithMetadata) ([]byte, error) {
+	if plan.AnchorGeoPose == nil {
+		return nil, errors.New("plan is not anchored at a GeoPose")
+	}
+	if plan.Plan == nil {
+		return nil, errors.New("plan has no steps")
+	}
+
+	// the geo plan smuggles the longitude, latitude & heading of each step in its poses
+	geoPlan := plan.Renderable()
+	coordinates := [][2]float64{}
+	headings := []float64{}
+	for i, step := range geoPlan.Path() {
+		pif, ok := step[plan.ComponentName.ShortName()]
+		if !ok {
+			return nil, fmt.Errorf("step %d of plan %s has no pose for component %s", i, plan.ID, plan.ComponentName)
+		}
+		pose := pif.Pose()
+		coordinates = append(coordinates, [2]float64{pose.Point().X, pose.Point().Y})
+		// the geo plan's theta is right handed, i.e. counter-clockwise from north
+		headings = append(headings, math.Mod(360-pose.Orientation().OrientationVectorDegrees().Theta, 360))
+	}
+	if len(coordinates) < 2 {
+		return nil, fmt.Errorf("plan %s must have at least 2 steps to be a LineString", plan.ID)
+	}
+
+	properties := map[string]interface{}{
+		"plan_id":        plan.ID.String(),
+		"execution_id":   plan.ExecutionID.String(),
+		"component_name": plan.ComponentName.String(),
+		"headings":       headings,
+	}
+	if plan.Label != "" {
+		properties["label"] = plan.Label
+	}
+	return json.Marshal(geoJSONFeature{
+		Type:       "Feature",
+		Geometry:   geoJSONLineString{Type: "LineString", Coordinates: coordinates},
+		Properties: properties,
+	})
+}
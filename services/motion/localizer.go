@@ -3,6 +3,8 @@ package motion
 import (
 	"context"
 	"math"
+	"sync"
+	"time"
 
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
@@ -10,6 +12,7 @@ import (
 
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/slam"
 	"go.viam.com/rdk/spatialmath"
 )
@@ -26,14 +29,45 @@ type Localizer interface {
 	CurrentPosition(context.Context) (*referenceframe.PoseInFrame, error)
 }
 
+// ErrStaleSLAMPosition is returned by a Localizer created with WithStalePositionTimeout when its SLAM
+// service has reported the same pose for longer than the timeout while the base was moving.
+var ErrStaleSLAMPosition = errors.New("slam position is stale, the slam service may be relocalizing")
+
 // slamLocalizer is a struct which only wraps an existing slam service.
 type slamLocalizer struct {
 	slam.Service
+
+	// staleTimeout is how long the pose may stay unchanged while moving is moving before it is considered
+	// stale, zero if it never is
+	staleTimeout time.Duration
+	moving       resource.Actuator
+	mu           sync.Mutex
+	lastPose     spatialmath.Pose
+	lastChanged  time.Time
+}
+
+// SLAMLocalizerOption configures a Localizer created by NewSLAMLocalizer.
+type SLAMLocalizerOption func(*slamLocalizer)
+
+// WithStalePositionTimeout makes the Localizer's CurrentPosition return ErrStaleSLAMPosition once the slam
+// service has reported an unchanged pose for longer than timeout while moving reports that it is moving,
+// as slam services may keep reporting their last pose while relocalizing. Callers should then pause
+// rather than navigate on the pose. The pose of a stationary base is expected to be unchanged, so it is
+// never stale while moving is still.
+func WithStalePositionTimeout(timeout time.Duration, moving resource.Actuator) SLAMLocalizerOption {
+	return func(s *slamLocalizer) {
+		s.staleTimeout = timeout
+		s.moving = moving
+	}
 }
 
 // NewSLAMLocalizer creates a new Localizer that relies on a slam service to report Pose.
-func NewSLAMLocalizer(slam slam.Service) Localizer {
-	return &slamLocalizer{Service: slam}
+func NewSLAMLocalizer(slam slam.Service, opts ...SLAMLocalizerOption) Localizer {
+	s := &slamLocalizer{Service: slam}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CurrentPosition returns slam's current position.
@@ -42,6 +76,13 @@ func (s *slamLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.Po
 	if err != nil {
 		return nil, err
 	}
+	stale, err := s.isStale(ctx, pose)
+	if err != nil {
+		return nil, err
+	}
+	if stale {
+		return nil, ErrStaleSLAMPosition
+	}
 	pose = spatialmath.Compose(pose, SLAMOrientationAdjustment)
 
 	// Slam poses are returned such that theta=0 points along the +X axis
@@ -49,6 +90,27 @@ func (s *slamLocalizer) CurrentPosition(ctx context.Context) (*referenceframe.Po
 	return referenceframe.NewPoseInFrame(referenceframe.World, pose), err
 }
 
+// isStale records pose & returns whether the slam service has reported it unchanged for longer than staleTimeout
+// while s.moving was moving.
+func (s *slamLocalizer) isStale(ctx context.Context, pose spatialmath.Pose) (bool, error) {
+	if s.staleTimeout <= 0 {
+		return false, nil
+	}
+	moving, err := s.moving.IsMoving(ctx)
+	if err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if !moving || s.lastPose == nil || !spatialmath.PoseAlmostEqual(pose, s.lastPose) {
+		s.lastPose = pose
+		s.lastChanged = now
+		return false, nil
+	}
+	return now.Sub(s.lastChanged) > s.staleTimeout, nil
+}
+
 // movementSensorLocalizer is a struct which only wraps an existing movementsensor.
 type movementSensorLocalizer struct {
 	movementsensor.MovementSensor
@@ -3,8 +3,11 @@ package motion_test
 import (
 	"context"
 	"math"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
 	"go.viam.com/test"
 
@@ -186,3 +189,62 @@ func TestCorrectStartPose(t *testing.T) {
 		test.That(t, err.Error(), test.ShouldEqual, "base appears to be pointing straight down, check your movement sensor")
 	})
 }
+
+func TestSLAMLocalizerStalePosition(t *testing.T) {
+	ctx := context.Background()
+	var pose atomic.Pointer[spatialmath.Pose]
+	setPose := func(p spatialmath.Pose) { pose.Store(&p) }
+	setPose(spatialmath.NewPoseFromPoint(r3.Vector{X: 1}))
+	slamSvc := inject.NewSLAMService("slam")
+	slamSvc.PositionFunc = func(ctx context.Context) (spatialmath.Pose, string, error) {
+		return *pose.Load(), "", nil
+	}
+
+	t.Run("unchanged poses are trusted without a stale position timeout", func(t *testing.T) {
+		localizer := motion.NewSLAMLocalizer(slamSvc)
+		for i := 0; i < 3; i++ {
+			_, err := localizer.CurrentPosition(ctx)
+			test.That(t, err, test.ShouldBeNil)
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+
+	var moving atomic.Bool
+	b := inject.NewBase("base")
+	b.IsMovingFunc = func(ctx context.Context) (bool, error) {
+		return moving.Load(), nil
+	}
+
+	t.Run("a pose unchanged for longer than the timeout while moving is stale", func(t *testing.T) {
+		staleTimeout := 50 * time.Millisecond
+		localizer := motion.NewSLAMLocalizer(slamSvc, motion.WithStalePositionTimeout(staleTimeout, b))
+
+		// the pose of a stationary base is expected to be unchanged
+		_, err := localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		time.Sleep(2 * staleTimeout)
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		moving.Store(true)
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		time.Sleep(2 * staleTimeout)
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeError, motion.ErrStaleSLAMPosition)
+
+		// or once the base stops
+		moving.Store(false)
+		_, err = localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+
+		// the pose is trusted again once it changes
+		setPose(spatialmath.NewPoseFromPoint(r3.Vector{X: 2}))
+		pif, err := localizer.CurrentPosition(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pif.Pose().Point().X, test.ShouldAlmostEqual, 2)
+	})
+}
@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/golang/geo/r3"
 	"github.com/google/uuid"
 	geo "github.com/kellydunn/golang-geo"
 	"github.com/pkg/errors"
 	pb "go.viam.com/api/service/motion/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/motionplan"
 	rprotoutils "go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
@@ -104,11 +106,49 @@ type StopPlanReq struct {
 	Extra         map[string]interface{}
 }
 
+// LabelExtraKey is the key of extra under which MoveOnGlobe & MoveOnMap accept
+// an optional label for the execution, and under which the Label filter of
+// ListPlanStatuses is sent over the api.
+const LabelExtraKey = "label"
+
+// RejectIfMovingExtraKey is the key of extra under which MoveOnGlobe & MoveOnMap accept an
+// optional bool. If true, a move for a component which has an active execution or reports that
+// it is moving is rejected with ErrBaseAlreadyMoving before it plans, rather than the move
+// cancelling other in progress motion service calls.
+const RejectIfMovingExtraKey = "reject_if_moving"
+
+// MinReplanIntervalExtraKey is the key of extra under which MoveOnGlobe & MoveOnMap accept the
+// optional minimum number of seconds between replans of the execution. Replans which aren't due
+// to a detected obstacle are deferred until it has elapsed.
+const MinReplanIntervalExtraKey = "min_replan_interval_sec"
+
+// StaleSLAMPositionTimeoutExtraKey is the key of extra under which MoveOnMap accepts the optional
+// number of seconds the slam service may report an unchanged position while the base is moving.
+// After that the position is considered stale, so the base is stopped and the execution replans.
+const StaleSLAMPositionTimeoutExtraKey = "stale_slam_position_timeout_sec"
+
 // ListPlanStatusesReq describes the request to ListPlanStatuses().
 type ListPlanStatusesReq struct {
 	// If true then only active plans will be returned.
 	OnlyActivePlans bool
-	Extra           map[string]interface{}
+	// If set then only plans of executions with this label will be returned.
+	Label string
+	// If set then only plans whose most recent status is at or after Since will be returned.
+	Since time.Time
+	// If set then only plans whose most recent status is at or before Until will be returned.
+	Until time.Time
+	Extra map[string]interface{}
+}
+
+// Includes returns whether a plan status' timestamp falls within the Since & Until of the request.
+func (r ListPlanStatusesReq) Includes(status PlanStatus) bool {
+	if !r.Since.IsZero() && status.Timestamp.Before(r.Since) {
+		return false
+	}
+	if !r.Until.IsZero() && status.Timestamp.After(r.Until) {
+		return false
+	}
+	return true
 }
 
 // PlanWithMetadata represents a motion plan with additional metadata used by the motion service.
@@ -123,6 +163,8 @@ type PlanWithMetadata struct {
 	motionplan.Plan
 	// The GPS point to anchor visualized plans at
 	AnchorGeoPose *spatialmath.GeoPose
+	// The label of the execution, if it was started with one
+	Label string
 }
 
 // PlanState denotes the state a Plan is in.
@@ -153,6 +195,47 @@ var TerminalStateSet = map[PlanState]struct{}{
 	PlanStateFailed:    {},
 }
 
+// ReplanReason categorizes why a Plan was replanned.
+type ReplanReason uint8
+
+const (
+	// ReplanReasonUnspecified denotes the Plan was not replanned or the reason is unknown.
+	ReplanReasonUnspecified ReplanReason = iota
+
+	// ReplanReasonObstacleDetected denotes an obstacle was detected along the Plan.
+	ReplanReasonObstacleDetected
+
+	// ReplanReasonPositionDrift denotes the component deviated too far from the Plan.
+	ReplanReasonPositionDrift
+
+	// ReplanReasonManual denotes replanning was requested by the caller.
+	ReplanReasonManual
+
+	// ReplanReasonFrameSystemChanged denotes the frame system changed during execution.
+	ReplanReasonFrameSystemChanged
+
+	// ReplanReasonOther denotes replanning was triggered for a reason not covered by the other values.
+	ReplanReasonOther
+)
+
+var replanReasonStrings = map[ReplanReason]string{
+	ReplanReasonObstacleDetected:   "obstacle_detected",
+	ReplanReasonPositionDrift:      "position_drift",
+	ReplanReasonManual:             "manual",
+	ReplanReasonFrameSystemChanged: "frame_system_changed",
+	ReplanReasonOther:              "other",
+}
+
+func (rr ReplanReason) String() string {
+	if s, ok := replanReasonStrings[rr]; ok {
+		return s
+	}
+	if rr == ReplanReasonUnspecified {
+		return "unspecified"
+	}
+	return "unknown"
+}
+
 // PlanID uniquely identifies a Plan.
 type PlanID = uuid.UUID
 
@@ -167,15 +250,20 @@ type PlanStatusWithID struct {
 	ComponentName resource.Name
 	ExecutionID   ExecutionID
 	Status        PlanStatus
+	// The label of the execution, if it was started with one
+	Label string
 }
 
 // PlanStatus describes the state of a given plan at a
 // point in time allong with an optional reason why the PlanStatus
 // transitioned to that state.
+// ReplanReason is set when the plan failed because replanning was triggered,
+// in which case Reason holds the free-form detail.
 type PlanStatus struct {
-	State     PlanState
-	Timestamp time.Time
-	Reason    *string
+	State        PlanState
+	Timestamp    time.Time
+	Reason       *string
+	ReplanReason ReplanReason
 }
 
 // PlanWithStatus contains a plan, its current status, and all state changes that came prior
@@ -185,6 +273,20 @@ type PlanWithStatus struct {
 	StatusHistory []PlanStatus
 }
 
+// Navigable is a mobile component which MoveOnGlobe and MoveOnMap are able to move. It is the subset
+// of the methods of a base.Base which they use. Any component implementing it can be navigated,
+// regardless of the API it is registered under.
+type Navigable interface {
+	resource.Resource
+	resource.Actuator
+	resource.Shaped
+
+	MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error
+	Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error
+	SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error
+	Properties(ctx context.Context, extra map[string]interface{}) (base.Properties, error)
+}
+
 // A Service controls the flow of moving components.
 type Service interface {
 	resource.Resource
@@ -286,21 +388,25 @@ func (pws PlanWithStatus) ToProto() *pb.PlanWithStatus {
 
 // ToProto converts a PlanStatusWithID to a *pb.PlanStatusWithID.
 func (ps PlanStatusWithID) ToProto() *pb.PlanStatusWithID {
-	return &pb.PlanStatusWithID{
+	psPB := &pb.PlanStatusWithID{
 		PlanId:        ps.PlanID.String(),
 		ComponentName: rprotoutils.ResourceNameToProto(ps.ComponentName),
 		ExecutionId:   ps.ExecutionID.String(),
 		Status:        ps.Status.ToProto(),
 	}
+	labelToProto(ps.Label, psPB)
+	return psPB
 }
 
 // ToProto converts a PlanStatus to a *pb.PlanStatus.
 func (ps PlanStatus) ToProto() *pb.PlanStatus {
-	return &pb.PlanStatus{
+	psPB := &pb.PlanStatus{
 		State:     ps.State.ToProto(),
 		Timestamp: timestamppb.New(ps.Timestamp),
 		Reason:    ps.Reason,
 	}
+	replanReasonToProto(ps.ReplanReason, psPB)
+	return psPB
 }
 
 // ToProto converts a Plan to a *pb.Plan.
@@ -312,26 +418,65 @@ func (p PlanWithMetadata) ToProto() *pb.Plan {
 		}
 	}
 
-	return &pb.Plan{
+	planPB := &pb.Plan{
 		Id:            p.ID.String(),
 		ComponentName: rprotoutils.ResourceNameToProto(p.ComponentName),
 		ExecutionId:   p.ExecutionID.String(),
 		Steps:         steps,
 	}
+	labelToProto(p.Label, planPB)
+	return planPB
+}
+
+// renderedPlan is a GeoPlan which Renderable substituted for a plan, so that Renderable
+// doesn't substitute it again.
+type renderedPlan struct {
+	motionplan.Plan
 }
 
 // Renderable returns a copy of the struct substituting its Plan for a GeoPlan consisting of smuggled global coordinates
 // This will only be done if the AnchorGeoPose field is non-nil, otherwise the original struct will be returned.
+// The AnchorGeoPose is kept & calling Renderable on a plan which is already renderable returns it unchanged.
 func (p PlanWithMetadata) Renderable() PlanWithMetadata {
 	if p.AnchorGeoPose == nil {
 		return p
 	}
-	return PlanWithMetadata{
-		ID:            p.ID,
-		ComponentName: p.ComponentName,
-		ExecutionID:   p.ExecutionID,
-		Plan:          motionplan.NewGeoPlan(p.Plan, p.AnchorGeoPose.Location()),
+	if _, rendered := p.Plan.(renderedPlan); rendered {
+		return p
+	}
+	p.Plan = renderedPlan{motionplan.NewGeoPlan(p.Plan, p.AnchorGeoPose.Location())}
+	return p
+}
+
+// PlanStepsEqual returns true if both plans have the same number of steps and each step has the same
+// frames at almost equal poses, e.g. to detect a replan which didn't change the path.
+func PlanStepsEqual(a, b motionplan.Path) bool {
+	return len(a) == len(b) && len(PlanStepsDiff(a, b)) == 0
+}
+
+// PlanStepsDiff returns the indices of the steps which differ between the two plans, in ascending order.
+// Steps are compared as in PlanStepsEqual & steps which only one of the plans has are always included.
+func PlanStepsDiff(a, b motionplan.Path) []int {
+	diff := []int{}
+	for i := 0; i < max(len(a), len(b)); i++ {
+		if i >= len(a) || i >= len(b) || !planStepEqual(a[i], b[i]) {
+			diff = append(diff, i)
+		}
+	}
+	return diff
+}
+
+func planStepEqual(a, b motionplan.PathStep) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for frame, aPose := range a {
+		bPose, ok := b[frame]
+		if !ok || aPose.Parent() != bPose.Parent() || !spatialmath.PoseAlmostEqual(aPose.Pose(), bPose.Pose()) {
+			return false
+		}
 	}
+	return true
 }
 
 // ToProto converts a PlanState to a pb.PlanState.
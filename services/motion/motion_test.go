@@ -1,6 +1,7 @@
 package motion
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"testing"
@@ -13,6 +14,7 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/motion/v1"
 	"go.viam.com/test"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -286,6 +288,221 @@ func TestPlanState(t *testing.T) {
 	})
 }
 
+func TestReplanReason(t *testing.T) {
+	t.Run("String()", func(t *testing.T) {
+		test.That(t, ReplanReasonUnspecified.String(), test.ShouldEqual, "unspecified")
+		test.That(t, ReplanReasonObstacleDetected.String(), test.ShouldEqual, "obstacle_detected")
+		test.That(t, ReplanReasonPositionDrift.String(), test.ShouldEqual, "position_drift")
+		test.That(t, ReplanReasonManual.String(), test.ShouldEqual, "manual")
+		test.That(t, ReplanReasonFrameSystemChanged.String(), test.ShouldEqual, "frame_system_changed")
+		test.That(t, ReplanReasonOther.String(), test.ShouldEqual, "other")
+		test.That(t, ReplanReason(60).String(), test.ShouldEqual, "unknown")
+	})
+
+	t.Run("survives a round trip through proto", func(t *testing.T) {
+		detail := "error state exceeds planDeviationMM"
+		bracketed := "[position_drift] detail"
+		timestamp := time.Now().UTC()
+		testCases := []PlanStatus{
+			{State: PlanStateFailed, Timestamp: timestamp},
+			{State: PlanStateFailed, Timestamp: timestamp, Reason: &detail},
+			{State: PlanStateFailed, Timestamp: timestamp, Reason: &bracketed},
+			{State: PlanStateFailed, Timestamp: timestamp, Reason: &detail, ReplanReason: ReplanReasonPositionDrift},
+			{State: PlanStateFailed, Timestamp: timestamp, Reason: &bracketed, ReplanReason: ReplanReasonObstacleDetected},
+			{State: PlanStateFailed, Timestamp: timestamp, ReplanReason: ReplanReasonManual},
+		}
+		for _, tc := range testCases {
+			// the status is marshaled as it is when sent over grpc
+			b, err := proto.Marshal(tc.ToProto())
+			test.That(t, err, test.ShouldBeNil)
+			var psPB pb.PlanStatus
+			test.That(t, proto.Unmarshal(b, &psPB), test.ShouldBeNil)
+			ps, err := planStatusFromProto(&psPB)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, ps, test.ShouldResemble, tc)
+		}
+	})
+
+	t.Run("doesn't change the proto reason", func(t *testing.T) {
+		detail := "obstacle in path"
+		ps := PlanStatus{State: PlanStateFailed, Reason: &detail, ReplanReason: ReplanReasonObstacleDetected}
+		test.That(t, *ps.ToProto().Reason, test.ShouldEqual, detail)
+	})
+
+	t.Run("is unspecified if unknown", func(t *testing.T) {
+		psPB := PlanStatus{State: PlanStateFailed}.ToProto()
+		setUndefinedProtoField(psPB, replanReasonProtoField, "not a replan reason")
+		ps, err := planStatusFromProto(psPB)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ps.ReplanReason, test.ShouldEqual, ReplanReasonUnspecified)
+	})
+}
+
+func TestListPlanStatusesReq(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Includes", func(t *testing.T) {
+		status := PlanStatus{Timestamp: now}
+		test.That(t, ListPlanStatusesReq{}.Includes(status), test.ShouldBeTrue)
+		test.That(t, ListPlanStatusesReq{Since: now}.Includes(status), test.ShouldBeTrue)
+		test.That(t, ListPlanStatusesReq{Until: now}.Includes(status), test.ShouldBeTrue)
+		test.That(t, ListPlanStatusesReq{Since: now.Add(-time.Second), Until: now.Add(time.Second)}.Includes(status), test.ShouldBeTrue)
+		test.That(t, ListPlanStatusesReq{Since: now.Add(time.Second)}.Includes(status), test.ShouldBeFalse)
+		test.That(t, ListPlanStatusesReq{Until: now.Add(-time.Second)}.Includes(status), test.ShouldBeFalse)
+	})
+
+	t.Run("survives a round trip through proto", func(t *testing.T) {
+		req := ListPlanStatusesReq{
+			OnlyActivePlans: true,
+			Label:           "delivery-run-42",
+			Since:           now.Add(-time.Hour).UTC(),
+			Until:           now.UTC(),
+			Extra:           map[string]interface{}{"foo": "bar"},
+		}
+		protoReq, err := req.toProto("motion")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, protoReq.Name, test.ShouldEqual, "motion")
+		res, err := listPlanStatusesReqFromProto(protoReq)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.OnlyActivePlans, test.ShouldBeTrue)
+		test.That(t, res.Label, test.ShouldEqual, req.Label)
+		test.That(t, res.Since.Equal(req.Since), test.ShouldBeTrue)
+		test.That(t, res.Until.Equal(req.Until), test.ShouldBeTrue)
+		test.That(t, res.Extra["foo"], test.ShouldEqual, "bar")
+
+		res, err = listPlanStatusesReqFromProto(&pb.ListPlanStatusesRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res.Since.IsZero(), test.ShouldBeTrue)
+		test.That(t, res.Until.IsZero(), test.ShouldBeTrue)
+	})
+
+	t.Run("returns an error for an invalid time", func(t *testing.T) {
+		extra, err := structpb.NewStruct(map[string]interface{}{"since": "not a time"})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = listPlanStatusesReqFromProto(&pb.ListPlanStatusesRequest{Extra: extra})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "could not parse since field")
+	})
+}
+
+func TestPlanStepsEqual(t *testing.T) {
+	step := func(x float64) motionplan.PathStep {
+		return motionplan.PathStep{"base": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: x}))}
+	}
+	a := motionplan.Path{step(0), step(10), step(20)}
+
+	t.Run("equal plans", func(t *testing.T) {
+		test.That(t, PlanStepsEqual(a, motionplan.Path{step(0), step(10), step(20)}), test.ShouldBeTrue)
+		test.That(t, PlanStepsDiff(a, motionplan.Path{step(0), step(10), step(20)}), test.ShouldBeEmpty)
+		// within tolerance
+		test.That(t, PlanStepsEqual(a, motionplan.Path{step(0), step(10 + 1e-9), step(20)}), test.ShouldBeTrue)
+		test.That(t, PlanStepsEqual(nil, motionplan.Path{}), test.ShouldBeTrue)
+	})
+
+	t.Run("reordered plans", func(t *testing.T) {
+		b := motionplan.Path{step(10), step(0), step(20)}
+		test.That(t, PlanStepsEqual(a, b), test.ShouldBeFalse)
+		test.That(t, PlanStepsDiff(a, b), test.ShouldResemble, []int{0, 1})
+	})
+
+	t.Run("differing plans", func(t *testing.T) {
+		b := motionplan.Path{step(0), step(15), step(20), step(30)}
+		test.That(t, PlanStepsEqual(a, b), test.ShouldBeFalse)
+		test.That(t, PlanStepsDiff(a, b), test.ShouldResemble, []int{1, 3})
+		test.That(t, PlanStepsDiff(b, a), test.ShouldResemble, []int{1, 3})
+
+		otherFrame := motionplan.Path{
+			step(0),
+			{"arm": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 10}))},
+			step(20),
+		}
+		test.That(t, PlanStepsDiff(a, otherFrame), test.ShouldResemble, []int{1})
+
+		otherParent := motionplan.Path{
+			step(0),
+			{"base": referenceframe.NewPoseInFrame("other", spatialmath.NewPoseFromPoint(r3.Vector{X: 10}))},
+			step(20),
+		}
+		test.That(t, PlanStepsDiff(a, otherParent), test.ShouldResemble, []int{1})
+	})
+}
+
+func TestPlanToGeoJSON(t *testing.T) {
+	myBase := base.Named("mybase")
+	anchor := spatialmath.NewGeoPose(geo.NewPoint(40, -73), 0)
+	// facing east, then west
+	east := spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90})
+	west := spatialmath.NewPose(r3.Vector{Y: 1000}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90})
+	steps := motionplan.Path{
+		{myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, east)},
+		{myBase.ShortName(): referenceframe.NewPoseInFrame(referenceframe.World, west)},
+	}
+	plan := PlanWithMetadata{
+		ID:            uuid.New(),
+		ComponentName: myBase,
+		ExecutionID:   uuid.New(),
+		Plan:          motionplan.NewSimplePlan(steps, nil),
+		AnchorGeoPose: anchor,
+		Label:         "label",
+	}
+
+	t.Run("returns a GeoJSON LineString of the plan's geo steps", func(t *testing.T) {
+		b, err := PlanToGeoJSON(plan)
+		test.That(t, err, test.ShouldBeNil)
+
+		var feature struct {
+			Type     string
+			Geometry struct {
+				Type        string
+				Coordinates [][]float64
+			}
+			Properties map[string]interface{}
+		}
+		test.That(t, json.Unmarshal(b, &feature), test.ShouldBeNil)
+		test.That(t, feature.Type, test.ShouldEqual, "Feature")
+		test.That(t, feature.Geometry.Type, test.ShouldEqual, "LineString")
+		test.That(t, len(feature.Geometry.Coordinates), test.ShouldEqual, 2)
+		for i, step := range steps {
+			expected := spatialmath.PoseToGeoPose(anchor, step[myBase.ShortName()].Pose()).Location()
+			test.That(t, feature.Geometry.Coordinates[i][0], test.ShouldAlmostEqual, expected.Lng())
+			test.That(t, feature.Geometry.Coordinates[i][1], test.ShouldAlmostEqual, expected.Lat())
+		}
+		// the first step is at the anchor & the second is north of it
+		test.That(t, feature.Geometry.Coordinates[0][0], test.ShouldAlmostEqual, -73)
+		test.That(t, feature.Geometry.Coordinates[0][1], test.ShouldAlmostEqual, 40)
+		test.That(t, feature.Geometry.Coordinates[1][0], test.ShouldAlmostEqual, -73)
+		test.That(t, feature.Geometry.Coordinates[1][1], test.ShouldBeGreaterThan, 40)
+		test.That(t, feature.Properties["plan_id"], test.ShouldEqual, plan.ID.String())
+		test.That(t, feature.Properties["execution_id"], test.ShouldEqual, plan.ExecutionID.String())
+		test.That(t, feature.Properties["label"], test.ShouldEqual, "label")
+		headings := feature.Properties["headings"].([]interface{})
+		test.That(t, len(headings), test.ShouldEqual, 2)
+		test.That(t, headings[0], test.ShouldAlmostEqual, 90, 1e-6)
+		test.That(t, headings[1], test.ShouldAlmostEqual, 270, 1e-6)
+
+		// renderable plans, as returned by PlanHistory, give the same result
+		renderable := plan.Renderable()
+		test.That(t, renderable.Renderable(), test.ShouldResemble, renderable)
+		renderableB, err := PlanToGeoJSON(renderable)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, renderableB, test.ShouldResemble, b)
+	})
+
+	t.Run("returns an error for plans which aren't geo plans", func(t *testing.T) {
+		nonGeoPlan := plan
+		nonGeoPlan.AnchorGeoPose = nil
+		_, err := PlanToGeoJSON(nonGeoPlan)
+		test.That(t, err, test.ShouldBeError, errors.New("plan is not anchored at a GeoPose"))
+	})
+
+	t.Run("returns an error for plans with fewer than 2 steps", func(t *testing.T) {
+		oneStepPlan := plan
+		oneStepPlan.Plan = motionplan.NewSimplePlan(steps[:1], nil)
+		_, err := PlanToGeoJSON(oneStepPlan)
+		test.That(t, err, test.ShouldBeError, fmt.Errorf("plan %s must have at least 2 steps to be a LineString", plan.ID))
+	})
+}
+
 func TestPlanStatusWithID(t *testing.T) {
 	t.Run("planStatusWithIDFromProto", func(t *testing.T) {
 		type testCase struct {
@@ -822,6 +1039,26 @@ func TestConfiguration(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("round trips through proto without rescaling PlanDeviationMM", func(t *testing.T) {
+		for _, planDeviationMM := range []float64{0, 1, 3, 150, 2600, 123.456} {
+			motionCfg := MotionConfiguration{
+				ObstacleDetectors:     obstacleDetectors,
+				LinearMPerSec:         1,
+				AngularDegsPerSec:     2,
+				PlanDeviationMM:       planDeviationMM,
+				PositionPollingFreqHz: 4,
+				ObstaclePollingFreqHz: 5,
+			}
+			pbCfg := motionCfg.toProto()
+			test.That(t, pbCfg.GetPlanDeviationM(), test.ShouldAlmostEqual, planDeviationMM/1000)
+
+			res := configurationFromProto(pbCfg)
+			test.That(t, res.PlanDeviationMM, test.ShouldAlmostEqual, planDeviationMM)
+			res.PlanDeviationMM = planDeviationMM
+			test.That(t, res, test.ShouldResemble, &motionCfg)
+		}
+	})
 }
 
 func TestMoveOnGlobeReq(t *testing.T) {
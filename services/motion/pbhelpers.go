@@ -1,7 +1,9 @@
 package motion
 
 import (
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/google/uuid"
 	geo "github.com/kellydunn/golang-geo"
@@ -9,6 +11,8 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/motion/v1"
 	vprotoutils "go.viam.com/utils/protoutils"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/rdk/motionplan"
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -53,12 +57,94 @@ func planStatusFromProto(ps *pb.PlanStatus) (PlanStatus, error) {
 	}
 
 	return PlanStatus{
-		State:     planStateFromProto(ps.State),
-		Reason:    ps.Reason,
-		Timestamp: ps.Timestamp.AsTime(),
+		State:        planStateFromProto(ps.State),
+		Reason:       ps.Reason,
+		ReplanReason: replanReasonFromProto(ps),
+		Timestamp:    ps.Timestamp.AsTime(),
 	}, nil
 }
 
+// The api's messages have no fields for some of the information the motion service reports, so it is
+// sent in fields the api doesn't define. Clients which don't know of them ignore them, so the fields the
+// api does define, e.g. a PlanStatus' Reason, are sent unchanged.
+const (
+	// replanReasonProtoField holds the name of a *pb.PlanStatus' ReplanReason.
+	replanReasonProtoField protowire.Number = 1000
+	// labelProtoField holds the Label of a *pb.PlanStatusWithID or *pb.Plan.
+	labelProtoField protowire.Number = 1000
+)
+
+// setUndefinedProtoField sets the field num, which m's api doesn't define, to value.
+func setUndefinedProtoField(m proto.Message, num protowire.Number, value string) {
+	r := m.ProtoReflect()
+	b := protowire.AppendTag(r.GetUnknown(), num, protowire.BytesType)
+	r.SetUnknown(protowire.AppendString(b, value))
+}
+
+// undefinedProtoField returns the last value of the field num, which m's api doesn't define, if it is set.
+func undefinedProtoField(m proto.Message, num protowire.Number) (string, bool) {
+	var value string
+	var found bool
+	b := m.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		fieldNum, fieldType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", false
+		}
+		b = b[n:]
+		if fieldNum == num && fieldType == protowire.BytesType {
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return "", false
+			}
+			value, found = v, true
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(fieldNum, fieldType, b)
+		if n < 0 {
+			return "", false
+		}
+		b = b[n:]
+	}
+	return value, found
+}
+
+// labelToProto sets the label of an execution on m, if it has one.
+func labelToProto(label string, m proto.Message) {
+	if label != "" {
+		setUndefinedProtoField(m, labelProtoField, label)
+	}
+}
+
+// labelFromProto returns the label set by labelToProto, or "" if there is none.
+func labelFromProto(m proto.Message) string {
+	label, _ := undefinedProtoField(m, labelProtoField)
+	return label
+}
+
+// replanReasonToProto sets the ReplanReason of ps on its *pb.PlanStatus, if it is specified.
+func replanReasonToProto(replanReason ReplanReason, ps *pb.PlanStatus) {
+	if s, ok := replanReasonStrings[replanReason]; ok {
+		setUndefinedProtoField(ps, replanReasonProtoField, s)
+	}
+}
+
+// replanReasonFromProto returns the ReplanReason set by replanReasonToProto, or
+// ReplanReasonUnspecified if there is none or it isn't known.
+func replanReasonFromProto(ps *pb.PlanStatus) ReplanReason {
+	s, ok := undefinedProtoField(ps, replanReasonProtoField)
+	if !ok {
+		return ReplanReasonUnspecified
+	}
+	for rr, rrString := range replanReasonStrings {
+		if rrString == s {
+			return rr
+		}
+	}
+	return ReplanReasonUnspecified
+}
+
 // planStatusWithIDFromProto converts a *pb.PlanStatus to a PlanStatus.
 func planStatusWithIDFromProto(ps *pb.PlanStatusWithID) (PlanStatusWithID, error) {
 	if ps == nil {
@@ -89,6 +175,7 @@ func planStatusWithIDFromProto(ps *pb.PlanStatusWithID) (PlanStatusWithID, error
 		ComponentName: rprotoutils.ResourceNameFromProto(ps.ComponentName),
 		ExecutionID:   executionID,
 		Status:        status,
+		Label:         labelFromProto(ps),
 	}, nil
 }
 
@@ -116,6 +203,7 @@ func planFromProto(p *pb.Plan) (PlanWithMetadata, error) {
 		ID:            id,
 		ComponentName: rprotoutils.ResourceNameFromProto(p.ComponentName),
 		ExecutionID:   executionID,
+		Label:         labelFromProto(p),
 	}
 
 	if len(p.Steps) == 0 {
@@ -275,6 +363,61 @@ func (req PlanHistoryReq) toProto(name string) (*pb.GetPlanRequest, error) {
 	}, nil
 }
 
+// The api's ListPlanStatusesRequest has no fields for the Label, Since & Until
+// filters so they are carried in extra.
+const (
+	sinceExtraKey = "since"
+	untilExtraKey = "until"
+)
+
+func (req ListPlanStatusesReq) toProto(name string) (*pb.ListPlanStatusesRequest, error) {
+	extra := make(map[string]interface{}, len(req.Extra)+3)
+	for k, v := range req.Extra {
+		extra[k] = v
+	}
+	if req.Label != "" {
+		extra[LabelExtraKey] = req.Label
+	}
+	if !req.Since.IsZero() {
+		extra[sinceExtraKey] = req.Since.Format(time.RFC3339Nano)
+	}
+	if !req.Until.IsZero() {
+		extra[untilExtraKey] = req.Until.Format(time.RFC3339Nano)
+	}
+	ext, err := vprotoutils.StructToStructPb(extra)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ListPlanStatusesRequest{
+		Name:            name,
+		OnlyActivePlans: req.OnlyActivePlans,
+		Extra:           ext,
+	}, nil
+}
+
+func listPlanStatusesReqFromProto(req *pb.ListPlanStatusesRequest) (ListPlanStatusesReq, error) {
+	r := ListPlanStatusesReq{OnlyActivePlans: req.GetOnlyActivePlans(), Extra: req.Extra.AsMap()}
+	if label, ok := r.Extra[LabelExtraKey].(string); ok {
+		r.Label = label
+	}
+	for key, t := range map[string]*time.Time{sinceExtraKey: &r.Since, untilExtraKey: &r.Until} {
+		raw, ok := r.Extra[key]
+		if !ok {
+			continue
+		}
+		s, ok := raw.(string)
+		if !ok {
+			return ListPlanStatusesReq{}, fmt.Errorf("could not interpret %s field as string", key)
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return ListPlanStatusesReq{}, errors.Wrapf(err, "could not parse %s field", key)
+		}
+		*t = parsed
+	}
+	return r, nil
+}
+
 func getPlanRequestFromProto(req *pb.GetPlanRequest) (PlanHistoryReq, error) {
 	if req.GetComponentName() == nil {
 		return PlanHistoryReq{}, errors.New("received nil *commonpb.ResourceName")
@@ -121,7 +121,10 @@ func (server *serviceServer) ListPlanStatuses(ctx context.Context, req *pb.ListP
 		return nil, err
 	}
 
-	r := ListPlanStatusesReq{OnlyActivePlans: req.GetOnlyActivePlans(), Extra: req.Extra.AsMap()}
+	r, err := listPlanStatusesReqFromProto(req)
+	if err != nil {
+		return nil, err
+	}
 	statuses, err := svc.ListPlanStatuses(ctx, r)
 	if err != nil {
 		return nil, err
@@ -23,6 +23,18 @@ const (
 	// TimeReceivedMetadataKey is optional metadata in the gRPC response header that correlates
 	// to the time right after the point cloud was captured.
 	TimeReceivedMetadataKey = "viam-time-received"
+
+	// PointCountMetadataKey is optional metadata in the gRPC response header containing the
+	// number of points in the returned point cloud.
+	PointCountMetadataKey = "viam-point-count"
+
+	// BoundsMinMetadataKey is optional metadata in the gRPC response header containing the
+	// minimum corner of the returned point cloud's bounding box formatted as "x,y,z".
+	BoundsMinMetadataKey = "viam-bounds-min"
+
+	// BoundsMaxMetadataKey is optional metadata in the gRPC response header containing the
+	// maximum corner of the returned point cloud's bounding box formatted as "x,y,z".
+	BoundsMaxMetadataKey = "viam-bounds-max"
 )
 
 // ContextWithMetadata attaches a metadata map to the context.